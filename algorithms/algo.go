@@ -1,52 +1,58 @@
 package algorithms
 
 import (
+	"cmp"
 	"fmt"
-	"math"
 )
 
-type SortingAlgorithm struct {
-	Name           string
-	SortFunc       func(arr []int)
-	TimeComplexity map[string]string
-}
-
 // HeapSort sorts an array using the HeapSort algorithm.
 func HeapSort(arr []int) {
+	HeapSortFunc(arr, cmp.Compare[int])
+}
+
+// HeapSortFunc sorts arr with HeapSort in the order defined by cmp, which
+// follows the cmp.Compare convention.
+func HeapSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	heapSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func heapSort[T any](s *sorter[T], arr []T) {
 	n := len(arr)
 
 	// Build a max heap
 	for i := n/2 - 1; i >= 0; i-- {
-		heapify(arr, n, i)
+		heapify(s, arr, n, i)
 	}
 
 	// Extract elements from the heap one by one
 	for i := n - 1; i > 0; i-- {
 		// Swap the root (maximum element) with the last element
-		arr[0], arr[i] = arr[i], arr[0]
+		s.swap(arr, 0, i)
 
 		// Call heapify on the reduced heap
-		heapify(arr, i, 0)
+		heapify(s, arr, i, 0)
 	}
 }
 
-func heapify(arr []int, n, i int) {
+func heapify[T any](s *sorter[T], arr []T, n, i int) {
+	s.enter()
+	defer s.leave()
 	largest := i
 	left := 2*i + 1
 	right := 2*i + 2
 
 	// Find the largest element among the root, left child, and right child
-	if left < n && arr[left] > arr[largest] {
+	if left < n && s.less(arr[largest], arr[left]) {
 		largest = left
 	}
-	if right < n && arr[right] > arr[largest] {
+	if right < n && s.less(arr[largest], arr[right]) {
 		largest = right
 	}
 
 	// If the largest element is not the root, swap them and continue to heapify
 	if largest != i {
-		arr[i], arr[largest] = arr[largest], arr[i]
-		heapify(arr, n, largest)
+		s.swap(arr, i, largest)
+		heapify(s, arr, n, largest)
 	}
 }
 
@@ -68,24 +74,38 @@ func heapify(arr []int, n, i int) {
 // the array with mergedSize elements. Alternatively, instead of allocating the array within the Merge()
 // function, a temporary array with the same size as the array being sorted can be passed as an argument.
 func MergeSort(arr []int) {
+	MergeSortFunc(arr, cmp.Compare[int])
+}
+
+// MergeSortFunc sorts arr with MergeSort in the order defined by cmp. It is
+// stable: elements that compare equal keep their original order.
+func MergeSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	mergeSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func mergeSort[T any](s *sorter[T], arr []T) {
 	if len(arr) <= 1 {
 		return
 	}
+	s.enter()
+	defer s.leave()
 
 	mid := len(arr) / 2
-	left := make([]int, mid)
-	right := make([]int, len(arr)-mid)
+	left := makeBuffer(s, mid)
+	right := makeBuffer(s, len(arr)-mid)
 
 	copy(left, arr[:mid])
 	copy(right, arr[mid:])
+	// Every element is copied out to a buffer and merged back once.
+	s.moved(2 * len(arr))
 
-	MergeSort(left)
-	MergeSort(right)
+	mergeSort(s, left)
+	mergeSort(s, right)
 
 	i, j, k := 0, 0, 0
 
 	for i < len(left) && j < len(right) {
-		if left[i] <= right[j] {
+		if !s.less(right[j], left[i]) {
 			arr[k] = left[i]
 			i++
 		} else {
@@ -113,92 +133,73 @@ func MergeSort(arr []int) {
 //   - makes use of "buckets" and is considered a type of bucket sort
 //   - bucket => a collection of integer values that all share a particular digit value (i.e., 57, 97, 77, and 17 all have 7 as 1s digit)
 //
-// Sorting Signed Integers
-// The above radix sort algorithm correctly sorts arrays of non-negative integers.
-// But if the array contains negative integers, the above algorithm would sort by
-// absolute value, so the integers are not correctly sorted. A small extension to
-// the algorithm correctly handles negative integers.
+// RadixSort is RadixSortBase in base 10. Rather than keeping a bucket per
+// digit, each pass counts the elements with each digit and uses the running
+// totals to copy every element straight to its place in a buffer.
 //
-// In the extension, before radix sort completes, the algorithm allocates two buckets,
-// one for negative integers and the other for non-negative integers. The algorithm
-// iterates through the array in order, placing negative integers in the negative
-// bucket and non-negative integers in the non-negative bucket. The algorithm then
-// reverses the order of the negative bucket and concatenates the buckets to yield a
-// sorted array.
+// Sorting Signed Integers
+// Sorting digits of the values themselves would order negative integers by
+// absolute value. Instead each value is offset by the smallest one, as an
+// unsigned number, so that every key is non-negative and in the same order as
+// the values, and only as many digits as the range max - min has are visited.
 func RadixSort(arr []int) {
-	maxDigits := RadixGetMaxLength(arr)
-
-	// Separate negative and non-negative integers
-	var negativeArr []int
-	var nonNegativeArr []int
-	for _, num := range arr {
-		if num < 0 {
-			negativeArr = append(negativeArr, -num)
-		} else {
-			nonNegativeArr = append(nonNegativeArr, num)
-		}
-	}
-	// inline radixSort: sorts an integer slice of non-negative numbers using Radix Sort.
-	radixSort := func(subArray []int, maxDigs int) {
-		for i := 0; i < maxDigs; i++ {
-			buckets := make([][]int, 10)
-
-			for _, num := range subArray {
-				digit := (num / int(math.Pow(10, float64(i)))) % 10
-				buckets[digit] = append(buckets[digit], num)
-			}
-
-			index := 0
-			for j := 0; j < 10; j++ {
-				for _, num := range buckets[j] {
-					subArray[index] = num
-					index++
-				}
-			}
-		}
-	}
+	radixSort(&sorter[int]{}, arr)
+}
 
-	// Sort negative and non-negative parts separately
-	radixSort(negativeArr, maxDigits)
-	radixSort(nonNegativeArr, maxDigits)
+// radixSort is RadixSort, recording its buffer traffic in s. It makes no comparisons.
+func radixSort(s *sorter[int], arr []int) {
+	radixSortBase(s, arr, 10)
+}
 
-	// Combine sorted arrays back with negative values restored
-	arrIndex := 0
-	for i := len(negativeArr) - 1; i >= 0; i-- {
-		arr[arrIndex] = -negativeArr[i]
-		arrIndex++
-	}
-	for _, num := range nonNegativeArr {
-		arr[arrIndex] = num
-		arrIndex++
+// magnitude returns the absolute value of num as an unsigned number, so that
+// even math.MinInt, whose absolute value overflows an int, is exact.
+func magnitude(num int) uint64 {
+	if num < 0 {
+		return -uint64(num)
 	}
+	return uint64(num)
 }
 
-// RadixGetMaxLength returns the number of digits in the maximum element in the array.
+// RadixGetMaxLength returns the number of digits in the element of the array
+// with the largest magnitude, or 1 for an empty array.
 func RadixGetMaxLength(arr []int) int {
-	max := arr[0]
+	var max uint64
 	for _, num := range arr {
-		if num < 0 {
-			num = -num // Make negative numbers positive for digit counting
-		}
-		if num > max {
-			max = num
+		// Compare magnitudes, so negative numbers count by their digits too
+		if m := magnitude(num); m > max {
+			max = m
 		}
 	}
-	return RadixGetLength(max)
+	return digitCount(max)
 }
 
-// RadixGetLength returns the number of digits in a given number.
+// RadixGetLength returns the number of digits in a given number, ignoring its sign.
 func RadixGetLength(num int) int {
-	if num == 0 {
-		return 1
+	return digitCount(magnitude(num))
+}
+
+func digitCount(num uint64) int {
+	digits := 1
+	for ; num >= 10; num /= 10 {
+		digits++
 	}
-	return int(math.Log10(float64(num))) + 1
+	return digits
 }
 
+// ShellSort sorts an array by insertion sorting elements gap apart, for a
+// shrinking sequence of gaps.
 func ShellSort(arr []int) {
+	ShellSortFunc(arr, cmp.Compare[int])
+}
+
+// ShellSortFunc sorts arr with ShellSort in the order defined by cmp.
+func ShellSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	shellSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func shellSort[T any](s *sorter[T], arr []T) {
 	n := len(arr)
-	gap := (len(arr) - 1) / 2
+	gap := n / 2
 
 	for gap > 0 {
 		for i := gap; i < n; i++ {
@@ -207,30 +208,44 @@ func ShellSort(arr []int) {
 
 			// Move elements of arr[0..i-gap] that are greater than temp
 			// to positions ahead of their current position
-			for j >= gap && arr[j-gap] > temp {
+			for j >= gap && s.less(temp, arr[j-gap]) {
 				arr[j] = arr[j-gap]
+				s.moved(1)
 				j -= gap
 			}
 
 			// Place temp (the current element) in its correct position
 			arr[j] = temp
+			s.moved(1)
 		}
-		// Reduce the gap for the next iteration
-		gap /= 10
+		// Halve the gap for the next iteration, ending with a plain insertion
+		// sort (gap 1) that leaves the array fully sorted.
+		gap /= 2
 	}
 }
 
+// SelectionSort sorts an array by repeatedly swapping the smallest remaining
+// element into place.
 func SelectionSort(arr []int) {
+	SelectionSortFunc(arr, cmp.Compare[int])
+}
+
+// SelectionSortFunc sorts arr with SelectionSort in the order defined by cmp.
+func SelectionSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	selectionSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func selectionSort[T any](s *sorter[T], arr []T) {
 	for i := 0; i < len(arr)-1; i++ {
 		// Find index of smallest remaining element
 		indexSmallest := i
 		for j := i + 1; j < len(arr); j++ {
-			if arr[j] < arr[indexSmallest] {
+			if s.less(arr[j], arr[indexSmallest]) {
 				indexSmallest = j
 			}
 		}
 		// Swap numbers[i] and numbers[indexSmallest
-		arr[i], arr[indexSmallest] = arr[indexSmallest], arr[i]
+		s.swap(arr, i, indexSmallest)
 	}
 }
 
@@ -246,6 +261,16 @@ func SelectionSort(arr []int) {
 //
 // The runtime for nearly sorted inputs is O((N - C) * 1 + C * N) = O(N).
 func InsertionSort(arr []int) {
+	InsertionSortFunc(arr, cmp.Compare[int])
+}
+
+// InsertionSortFunc sorts arr with InsertionSort in the order defined by cmp.
+// It is stable.
+func InsertionSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	insertionSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func insertionSort[T any](s *sorter[T], arr []T) {
 	n := len(arr)
 
 	for i := 1; i < n; i++ {
@@ -254,13 +279,15 @@ func InsertionSort(arr []int) {
 
 		// Move elements of arr[0..i-1] that are greater than key
 		// to one position ahead of their current position
-		for j >= 0 && arr[j] > key {
+		for j >= 0 && s.less(key, arr[j]) {
 			arr[j+1] = arr[j]
+			s.moved(1)
 			j--
 		}
 
 		// Place the key in its correct position
 		arr[j+1] = key
+		s.moved(1)
 	}
 }
 
@@ -274,30 +301,42 @@ func InsertionSort(arr []int) {
 // If the pivot yields two equal-sized parts, then there will be log N levels,
 // requiring the N * log N comparisons.
 func QuickSort(arr []int) {
+	QuickSortFunc(arr, cmp.Compare[int])
+}
+
+// QuickSortFunc sorts arr with QuickSort in the order defined by cmp.
+func QuickSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	quickSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func quickSort[T any](s *sorter[T], arr []T) {
 	low := 0
 	high := len(arr) - 1
 	if low < high {
+		s.enter()
+		defer s.leave()
 		// Partition the array and get the index of the pivot element
-		pivotIndex := partition(arr, low, high)
+		pivotIndex := partition(s, arr, low, high)
 
-		// Recursively sort the elements in the left and right partitions
-		QuickSort(arr[:pivotIndex])
-		QuickSort(arr[pivotIndex+1 : high])
+		// Recursively sort the elements in the left and right partitions:
+		// arr[:pivotIndex+1] holds the elements <= pivot, arr[pivotIndex+1:] those >= pivot.
+		quickSort(s, arr[:pivotIndex+1])
+		quickSort(s, arr[pivotIndex+1:])
 	}
 }
 
-func partition(arr []int, low int, high int) int {
+func partition[T any](s *sorter[T], arr []T, low int, high int) int {
 	// Choose the middle element as the pivot
 	mid := low + (high-low)/2
 	pivot := arr[mid]
 	var done bool
 	for !done {
 		// Increment low while numbers[lowIndex] < pivot
-		for arr[low] < pivot {
+		for s.less(arr[low], pivot) {
 			low++
 		}
 		// Decrement high while pivot < numbers[highIndex]
-		for pivot < arr[high] {
+		for s.less(pivot, arr[high]) {
 			high--
 		}
 		// If zero or one elements remain, then all numbers are
@@ -306,7 +345,7 @@ func partition(arr []int, low int, high int) int {
 			done = true
 		} else {
 			// Swap arr[lowIndex] and arr[highIndex]
-			arr[low], arr[high] = arr[high], arr[low]
+			s.swap(arr, low, high)
 			// Finish out incrementing low and high indx
 			low++
 			high--
@@ -1,114 +1,513 @@
 package algorithms
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"math/rand"
+	"runtime"
+	"slices"
 	"time"
+
+	"github.com/ryanuber/columnize"
 )
 
 func prettyPrintMap(m map[string]string) {
-	for key, value := range m {
-		fmt.Printf("  · %s: %s\n", key, value)
-	}
-}
-
-func generateRandomArray(size int) []int {
-	rand.Seed(time.Now().UnixNano())
-	arr := make([]int, size)
-	for i := range arr {
-		arr[i] = rand.Intn(1000000) // Adjust the range as needed
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		fmt.Printf("  · %s: %s\n", key, m[key])
 	}
-	return arr
 }
 
-func benchmarkSortAlgorithm(arrSize int, sortFunc func([]int)) time.Duration {
-	arr := generateRandomArray(arrSize)
-	startTime := time.Now()
+// benchmarkSortAlgorithm times sortFunc sorting a copy of arr. Copying is not
+// part of the time.
+func benchmarkSortAlgorithm(arr []int, sortFunc func([]int)) time.Duration {
 	sortedArr := make([]int, len(arr))
 	copy(sortedArr, arr)
+	startTime := time.Now()
 	sortFunc(sortedArr)
 	return time.Since(startTime)
 }
 
-func BenchmarkSortAlgorithms() {
-	sortingAlgorithms := []SortingAlgorithm{
-		{
-			Name:     "QuickSort",
-			SortFunc: QuickSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(nlogn)",
-				"Avg Case":   "θ(nlogn)",
-				"Worst Case": "O(n²)",
-			},
-		},
-		{
-			Name:     "HeapSort",
-			SortFunc: HeapSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(nlogn)",
-				"Avg Case":   "θ(nlogn)",
-				"Worst Case": "O(nlogn)",
-			},
-		},
-		{
-			Name:     "RadixSort",
-			SortFunc: RadixSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(nk)",
-				"Avg Case":   "θ(nk)",
-				"Worst Case": "O(nk)",
-				"k":          "largest num of digits",
-			},
-		},
-		{
-			Name:     "MergeSort",
-			SortFunc: MergeSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(nlogn)",
-				"Avg Case":   "θ(nlogn)",
-				"Worst Case": "O(nlogn)",
-			},
-		},
-		{
-			Name:     "ShellSort",
-			SortFunc: ShellSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(nlog²n)",
-				"Avg Case":   "θ(nlog²n) <= between => θ(n²)",
-				"Worst Case": "O(n²)",
-			},
-		},
-		{
-			Name:     "InsertionSort",
-			SortFunc: InsertionSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(n²)",
-				"Avg Case":   "θ(n²)",
-				"Worst Case": "O(n²)",
-			},
-		},
-		{
-			Name:     "SelectionSort",
-			SortFunc: SelectionSort,
-			TimeComplexity: map[string]string{
-				"Best Case":  "Ω(n²)",
-				"Avg Case":   "θ(n²)",
-				"Worst Case": "O(n²)",
-			},
-		},
-	}
-
-	inputSizes := []int{1000, 10000, 50000, 100000, 150000, 200000, 250000, 300000}
-
-	// Run the benchmarks
-	for _, algo := range sortingAlgorithms {
+// BenchmarkConfig configures RunBenchmark. Every cell of the benchmark, one
+// algorithm sorting one distribution at one size, sorts the same input in
+// every trial, and the input depends only on Seed, the distribution and the
+// size, so runs with the same config sort the same inputs.
+//
+// A cell that does not finish, warmup and counting included, within
+// CellBudget, or once the algorithm has spent AlgorithmBudget on all its
+// cells, is abandoned. It and the larger sizes of its distribution are then
+// estimated rather than measured. Zero budgets are unlimited. Only the untimed
+// sorts check the budget as they go, so that the timed ones carry no more
+// overhead than the reference's; a timed sort starts only if the budget leaves
+// it as long as the previous sort took. With a budget, every cell therefore
+// warms up at least once.
+type BenchmarkConfig struct {
+	Sizes         []int               `json:"sizes"`         // input sizes, smallest first
+	Trials        int                 `json:"trials"`        // timed sorts per cell
+	Warmup        int                 `json:"warmup"`        // untimed sorts per cell before the timed ones
+	Seed          int64               `json:"seed"`          // seeds every input
+	Distributions []InputDistribution `json:"distributions"` // input shapes to sort
+	// Algorithms names the registered algorithms to run, in order. Nil runs
+	// every one of SortingAlgorithms. The reference, slices.Sort, is run last
+	// if not named.
+	Algorithms      []string      `json:"algorithms,omitempty"`
+	CellBudget      time.Duration `json:"cell_budget_ns,omitempty"`
+	AlgorithmBudget time.Duration `json:"algorithm_budget_ns,omitempty"`
+	// ProfileDir, if set, is the directory to write a CPU and a heap profile
+	// of each algorithm's cells to, as <algorithm>.cpu.pprof and
+	// <algorithm>.heap.pprof. The CPU profiler allocates in the background,
+	// which can show in the cells' Memory while profiling.
+	ProfileDir string `json:"profile_dir,omitempty"`
+}
+
+// DefaultBenchmarkConfig returns the configuration BenchmarkSortAlgorithms runs.
+func DefaultBenchmarkConfig() BenchmarkConfig {
+	return BenchmarkConfig{
+		Sizes:         []int{1000, 10000, 50000, 100000, 150000, 200000, 250000, 300000},
+		Trials:        10, // with fewer than four, CompareBenchmarks can find no change significant
+		Warmup:        1,
+		Seed:          1,
+		Distributions: InputDistributions(),
+		// Enough for the n log n sorts to measure every size, while the n² ones
+		// stop around 50000 rather than spending minutes on each larger input.
+		CellBudget:      5 * time.Second,
+		AlgorithmBudget: time.Minute,
+	}
+}
+
+// algorithms returns the algorithms c selects, always including the reference
+// algorithm, or an error naming any that are not registered.
+func (c BenchmarkConfig) algorithms() ([]SortingAlgorithm, error) {
+	registered := SortingAlgorithms()
+	if c.Algorithms == nil {
+		return registered, nil
+	}
+	names := c.Algorithms
+	if !slices.Contains(names, referenceAlgorithm) {
+		names = append(slices.Clip(names), referenceAlgorithm)
+	}
+	var algos []SortingAlgorithm
+	for _, name := range names {
+		i := slices.IndexFunc(registered, func(a SortingAlgorithm) bool { return a.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown sorting algorithm %q", name)
+		}
+		algos = append(algos, registered[i])
+	}
+	return algos, nil
+}
+
+func (c BenchmarkConfig) validate() error {
+	var errs []error
+	if c.Trials < 1 {
+		errs = append(errs, fmt.Errorf("trials must be at least 1, got %d", c.Trials))
+	}
+	if c.Warmup < 0 {
+		errs = append(errs, fmt.Errorf("warmup must not be negative, got %d", c.Warmup))
+	}
+	if !slices.IsSorted(c.Sizes) || slices.ContainsFunc(c.Sizes, func(size int) bool { return size < 0 }) {
+		errs = append(errs, fmt.Errorf("sizes must be non-negative and increasing, got %v", c.Sizes))
+	}
+	if c.CellBudget < 0 || c.AlgorithmBudget < 0 {
+		errs = append(errs, fmt.Errorf("budgets must not be negative, got %s per cell and %s per algorithm", c.CellBudget, c.AlgorithmBudget))
+	}
+	return errors.Join(errs...)
+}
+
+// BenchmarkCell is the result of one algorithm sorting one distribution at one size.
+type BenchmarkCell struct {
+	Algorithm    string            `json:"algorithm"`
+	Distribution InputDistribution `json:"distribution"`
+	Size         int               `json:"size"`
+	// RangeDigits is the number of decimal digits in the input's max - min,
+	// the k of sorts that take O(nk) time.
+	RangeDigits int             `json:"range_digits"`
+	Times       []time.Duration `json:"times_ns"` // one per trial, in the order they ran
+	Min         time.Duration   `json:"min_ns"`
+	Median      time.Duration   `json:"median_ns"`
+	Mean        time.Duration   `json:"mean_ns"`
+	StdDev      time.Duration   `json:"stddev_ns"` // sample standard deviation; 0 for a single trial
+	// Counts are the operation counts of an instrumented sort of the same
+	// input, or nil if the algorithm is not instrumented.
+	Counts *OpCounts `json:"counts,omitempty"`
+	// Memory is the memory a separate untimed sort of the same input used.
+	Memory *MemoryStats `json:"memory,omitempty"`
+	// Estimated is set for a cell that ran out of budget, or was skipped after
+	// a smaller one did. It has no Times, Counts or Memory, and Min, Median and Mean
+	// are all extrapolated from the algorithm's smaller measured sizes, or are
+	// zero if there were none.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// BenchmarkResults holds a benchmark run's configuration and every cell it measured.
+type BenchmarkResults struct {
+	Config BenchmarkConfig `json:"config"`
+	Cells  []BenchmarkCell `json:"cells"` // by algorithm, then distribution, then size
+}
+
+// RunBenchmark runs every algorithm selected by cfg on every distribution and
+// size, warming up and then timing cfg.Trials sorts per cell.
+func RunBenchmark(cfg BenchmarkConfig) (BenchmarkResults, error) {
+	return RunBenchmarkContext(context.Background(), cfg)
+}
+
+// RunBenchmarkContext is RunBenchmark, stopping once ctx is done. It then
+// returns the cells of the algorithms that finished, and ctx's error.
+func RunBenchmarkContext(ctx context.Context, cfg BenchmarkConfig) (BenchmarkResults, error) {
+	results := BenchmarkResults{Config: cfg}
+	if err := cfg.validate(); err != nil {
+		return results, err
+	}
+	algos, err := cfg.algorithms()
+	if err != nil {
+		return results, err
+	}
+	for _, algo := range algos {
+		cells, err := runBenchmarkAlgorithm(ctx, cfg, algo)
+		if err != nil {
+			return results, err
+		}
+		results.Cells = append(results.Cells, cells...)
+	}
+	return results, nil
+}
+
+// runBenchmarkAlgorithm runs every cell of algo within cfg.AlgorithmBudget. It
+// goes size by size, so that every distribution is measured at the smaller
+// sizes before the budget can run out. Once a cell runs out of budget, it and
+// the larger sizes of its distribution are estimated instead.
+func runBenchmarkAlgorithm(ctx context.Context, cfg BenchmarkConfig, algo SortingAlgorithm) (_ []BenchmarkCell, err error) {
+	if cfg.ProfileDir != "" {
+		stop, profileErr := startProfiles(cfg.ProfileDir, algo.Name)
+		if profileErr != nil {
+			return nil, profileErr
+		}
+		defer func() {
+			err = errors.Join(err, stop())
+		}()
+	}
+	algoCtx := ctx
+	if cfg.AlgorithmBudget > 0 {
+		var cancel context.CancelFunc
+		algoCtx, cancel = context.WithTimeout(ctx, cfg.AlgorithmBudget)
+		defer cancel()
+	}
+	// cells is ordered by distribution, then size, as in BenchmarkResults.
+	cells := make([]BenchmarkCell, len(cfg.Distributions)*len(cfg.Sizes))
+	overBudget := make([]bool, len(cfg.Distributions))
+	for j, size := range cfg.Sizes {
+		for i, dist := range cfg.Distributions {
+			input := dist.Generate(size, rand.New(rand.NewSource(inputSeed(cfg.Seed, dist, size))))
+			series := cells[i*len(cfg.Sizes) : (i+1)*len(cfg.Sizes)]
+			if !overBudget[i] {
+				cell, err := runBenchmarkCell(algoCtx, cfg, algo, dist, input)
+				if err == nil {
+					series[j] = cell
+					continue
+				}
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				overBudget[i] = true
+			}
+			series[j] = estimateBenchmarkCell(algo, dist, input, series[:j])
+		}
+	}
+	return cells, nil
+}
+
+// runBenchmarkCell measures algo sorting input within cfg.CellBudget, and
+// returns ctx's error if the budget or ctx runs out first.
+func runBenchmarkCell(ctx context.Context, cfg BenchmarkConfig, algo SortingAlgorithm, dist InputDistribution, input []int) (BenchmarkCell, error) {
+	if cfg.CellBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.CellBudget)
+		defer cancel()
+	}
+	sort, release := algo.sortContextFunc(ctx)
+	defer release()
+	var err error
+	sortFunc := func(arr []int) {
+		err = sort(arr)
+	}
+
+	cell := BenchmarkCell{Algorithm: algo.Name, Distribution: dist, Size: len(input), RangeDigits: rangeDigits(input)}
+	warmup := cfg.Warmup
+	if ctx.Done() != nil {
+		warmup = max(warmup, 1)
+	}
+	var last time.Duration
+	for i := 0; i < warmup && err == nil; i++ {
+		last = benchmarkSortAlgorithm(input, sortFunc)
+	}
+	// The timed sorts run SortFunc, which never checks ctx, as slices.Sort does
+	// not; the budget is checked between them instead.
+	for i := 0; i < cfg.Trials && err == nil; i++ {
+		if err = fitsBudget(ctx, last); err == nil {
+			last = benchmarkSortAlgorithm(input, algo.SortFunc)
+			cell.Times = append(cell.Times, last)
+		}
+	}
+	if err != nil {
+		return cell, err
+	}
+	cell.Min, cell.Median, cell.Mean, cell.StdDev = durationStats(cell.Times)
+	counts, ok, err := algo.countContext(ctx, slices.Clone(input))
+	if err != nil {
+		return cell, err
+	}
+	if ok {
+		cell.Counts = &counts
+	}
+	memory, err := measureMemory(sort, input)
+	if err != nil {
+		return cell, err
+	}
+	cell.Memory = &memory
+	return cell, nil
+}
+
+// fitsBudget returns ctx's error if it is done, or context.DeadlineExceeded if
+// its deadline leaves less than d.
+func fitsBudget(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// estimateBenchmarkCell returns an estimated cell for algo sorting input,
+// extrapolated from the measured cells of smaller sizes of the same distribution.
+func estimateBenchmarkCell(algo SortingAlgorithm, dist InputDistribution, input []int, smaller []BenchmarkCell) BenchmarkCell {
+	cell := BenchmarkCell{
+		Algorithm:    algo.Name,
+		Distribution: dist,
+		Size:         len(input),
+		RangeDigits:  rangeDigits(input),
+		Estimated:    true,
+	}
+	if t, ok := extrapolateTime(algo, smaller, cell.Size, cell.RangeDigits); ok {
+		cell.Min, cell.Median, cell.Mean = t, t, t
+	}
+	return cell
+}
+
+// rangeDigits returns the number of decimal digits in max(arr) - min(arr).
+func rangeDigits(arr []int) int {
+	if len(arr) == 0 {
+		return 1
+	}
+	return digitCount(uint64(slices.Max(arr)) - uint64(slices.Min(arr)))
+}
+
+// durationStats returns the minimum, median, mean and sample standard
+// deviation of times, which must not be empty.
+func durationStats(times []time.Duration) (minimum, median, mean, stdDev time.Duration) {
+	sorted := slices.Sorted(slices.Values(times))
+	minimum = sorted[0]
+	if n := len(sorted); n%2 == 1 {
+		median = sorted[n/2]
+	} else {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	var sum float64
+	for _, t := range times {
+		sum += float64(t)
+	}
+	avg := sum / float64(len(times))
+	mean = time.Duration(avg)
+	if len(times) > 1 {
+		var squares float64
+		for _, t := range times {
+			squares += (float64(t) - avg) * (float64(t) - avg)
+		}
+		stdDev = time.Duration(math.Sqrt(squares / float64(len(times)-1)))
+	}
+	return minimum, median, mean, stdDev
+}
+
+// Table renders the cells of the named algorithm as a columnized table, one
+// row per distribution and size.
+func (r BenchmarkResults) Table(algorithm string) string {
+	rows := []string{"\x1fDistribution\x1fSize\x1fMin\x1fMedian\x1fMean\x1fStdDev\x1fOperations\x1fMemory\x1f\x1f"}
+	for _, cell := range r.Cells {
+		if cell.Algorithm != algorithm {
+			continue
+		}
+		if cell.Estimated {
+			rows = append(rows, fmt.Sprintf("\x1f%s\x1f%d\x1f-\x1f%s\x1f-\x1f-\x1f-\x1f-\x1fESTIMATE\x1f",
+				cell.Distribution, cell.Size, estimatedDuration(cell.Median)))
+			continue
+		}
+		counts, memory := "-", "-"
+		if cell.Counts != nil {
+			counts = cell.Counts.String()
+		}
+		if cell.Memory != nil {
+			memory = cell.Memory.String()
+		}
+		rows = append(rows, fmt.Sprintf("\x1f%s\x1f%d\x1f%s\x1f%s\x1f%s\x1f%s\x1f%s\x1f%s\x1f\x1f",
+			cell.Distribution, cell.Size, cell.Min, cell.Median, cell.Mean, cell.StdDev, counts, memory))
+	}
+	return columnize.Format(rows, &columnize.Config{Delim: string([]byte{0x1f}), Glue: "  "})
+}
+
+// estimatedDuration formats an estimated time, marked with a "≈" and rounded
+// to three or four significant digits, or "?" if there was nothing to
+// extrapolate it from.
+func estimatedDuration(d time.Duration) string {
+	if d <= 0 {
+		return "?"
+	}
+	unit := time.Microsecond
+	for unit < d/1000 {
+		unit *= 10
+	}
+	return "≈" + d.Round(unit).String()
+}
+
+// Print prints each algorithm's declared time complexity followed by its Table.
+func (r BenchmarkResults) Print() {
+	algos, _ := r.Config.algorithms()
+	for _, algo := range algos {
 		fmt.Println("Algorithm:", algo.Name)
 		fmt.Println("Time Complexity:")
 		prettyPrintMap(algo.TimeComplexity)
-		for _, size := range inputSizes {
-			duration := benchmarkSortAlgorithm(size, algo.SortFunc)
-			fmt.Printf("Input Size %d: %s\n", size, duration)
-		}
+		fmt.Println(r.Table(algo.Name))
 		fmt.Println()
 	}
 }
+
+// BenchmarkSortAlgorithms runs DefaultBenchmarkConfig and prints the results:
+// for every algorithm, distribution and size, the spread of the trials' times
+// and the algorithm's operation counts and memory use for the same input, each
+// taken in a separate run so that measuring them does not skew the time. Cells
+// beyond the time budgets, such as SelectionSort's larger inputs, are
+// estimated and marked as such. It then compares the parallel sorts with their
+// serial counterparts.
+func BenchmarkSortAlgorithms() {
+	cfg := DefaultBenchmarkConfig()
+	results, err := RunBenchmark(cfg)
+	if err != nil {
+		fmt.Println("Benchmark:", err)
+		return
+	}
+	results.Print()
+	fmt.Println("Complexity Fit:")
+	fmt.Println(ComplexityTable(results.FitComplexity()))
+	fmt.Println()
+
+	size := cfg.Sizes[len(cfg.Sizes)-1]
+	benchmarkParallelSorts(UniformInput.Generate(size, rand.New(rand.NewSource(inputSeed(cfg.Seed, UniformInput, size)))))
+}
+
+// parallelBaselines pairs each parallel sort with the serial sort it is
+// measured against.
+var parallelBaselines = [][2]string{
+	{"ParallelMergeSort", "MergeSort"},
+	{"ParallelQuickSort", "QuickSort"},
+	{"SampleSort", "PdqSort"},
+}
+
+// benchmarkParallelSorts times each parallel sort and its serial counterpart
+// on arr under GOMAXPROCS settings doubling from 1 to the number of CPUs, and
+// prints the speedup of one over the other.
+func benchmarkParallelSorts(arr []int) {
+	algos := make(map[string]SortingAlgorithm)
+	for _, algo := range SortingAlgorithms() {
+		algos[algo.Name] = algo
+	}
+	var procs []int
+	for p := 1; p < runtime.NumCPU(); p *= 2 {
+		procs = append(procs, p)
+	}
+	procs = append(procs, runtime.NumCPU())
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	fmt.Printf("Parallel Speedup (Input Size %d):\n", len(arr))
+	for _, p := range procs {
+		runtime.GOMAXPROCS(p)
+		for _, pair := range parallelBaselines {
+			parallel, serial := algos[pair[0]], algos[pair[1]]
+			parallelTime := benchmarkSortAlgorithm(arr, parallel.SortFunc)
+			serialTime := benchmarkSortAlgorithm(arr, serial.SortFunc)
+			fmt.Printf("GOMAXPROCS %d: %s %s | %s %s | speedup %.2fx\n",
+				p, parallel.Name, parallelTime, serial.Name, serialTime, float64(serialTime)/float64(parallelTime))
+		}
+	}
+}
+
+// TestBenchmarkBudget runs the quadratic sorts against a faster one under a
+// tight time budget, and prints the results with the cells that ran out of
+// budget estimated.
+func TestBenchmarkBudget() {
+	start := time.Now()
+	results, err := RunBenchmark(BenchmarkConfig{
+		Sizes:           []int{1000, 2000, 5000, 10000, 50000, 100000, 300000},
+		Trials:          3,
+		Seed:            1,
+		Distributions:   []InputDistribution{UniformInput, SortedInput},
+		Algorithms:      []string{"SelectionSort", "InsertionSort", "MergeSort"},
+		CellBudget:      250 * time.Millisecond,
+		AlgorithmBudget: 2 * time.Second,
+	})
+	if err != nil {
+		fmt.Println("Benchmark:", err)
+		return
+	}
+	results.Print()
+	fmt.Println("Finished in", time.Since(start).Round(time.Millisecond))
+}
+
+// TestBenchmarkRunner runs a small benchmark twice with the same seed, prints
+// the first run and its complexity fit, and reports whether the seed
+// reproduces every input and whether both runs counted the same operations.
+func TestBenchmarkRunner() {
+	cfg := BenchmarkConfig{
+		Sizes:         []int{100, 1000, 5000},
+		Trials:        5,
+		Warmup:        1,
+		Seed:          42,
+		Distributions: InputDistributions(),
+		Algorithms:    []string{"InsertionSort", "PdqSort", "TimSort"},
+	}
+	first, err := RunBenchmark(cfg)
+	if err != nil {
+		fmt.Println("Benchmark:", err)
+		return
+	}
+	second, err := RunBenchmark(cfg)
+	if err != nil {
+		fmt.Println("Second benchmark:", err)
+		return
+	}
+	first.Print()
+	fmt.Println(ComplexityTable(first.FitComplexity()))
+
+	reproducible := true
+	for _, dist := range cfg.Distributions {
+		for _, size := range cfg.Sizes {
+			a := dist.Generate(size, rand.New(rand.NewSource(inputSeed(cfg.Seed, dist, size))))
+			b := dist.Generate(size, rand.New(rand.NewSource(inputSeed(cfg.Seed, dist, size))))
+			if !slices.Equal(a, b) {
+				fmt.Printf("%s input of size %d differs between generations\n", dist, size)
+				reproducible = false
+			}
+		}
+	}
+	fmt.Println("Inputs reproducible from seed:", reproducible)
+
+	// The reference algorithm is not instrumented and has no counts to compare.
+	same := len(first.Cells) == len(second.Cells)
+	for i := 0; same && i < len(first.Cells); i++ {
+		if first.Cells[i].Counts != nil && second.Cells[i].Counts != nil {
+			same = *first.Cells[i].Counts == *second.Cells[i].Counts
+		}
+	}
+	fmt.Println("Operation counts equal across runs:", same)
+}
@@ -0,0 +1,464 @@
+package algorithms
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/ryanuber/columnize"
+)
+
+const (
+	defaultSignificanceLevel   = 0.05
+	defaultRegressionThreshold = 0.10
+	// exactMannWhitneyMax is the largest number of trials per run for which
+	// the Mann-Whitney U test's p-value is computed exactly, when no times
+	// tie; beyond it, or with ties, it is approximated with a normal distribution.
+	exactMannWhitneyMax = 50
+)
+
+// ErrBenchmarkRegression is returned by CheckBenchmarkBaseline, wrapped with
+// the details, for every algorithm that regressed.
+var ErrBenchmarkRegression = errors.New("benchmark regression")
+
+// ErrTooFewTrials is returned by CheckBenchmarkBaseline, wrapped with the
+// details, when the runs have too few trials for any change to be significant,
+// so that a regression could not have been detected.
+var ErrTooFewTrials = errors.New("too few trials to detect a regression")
+
+// SaveBaseline writes the results to path as JSON, for later runs to be
+// compared against with CompareBenchmarks.
+func (r BenchmarkResults) SaveBaseline(path string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return r.WriteJSON(f)
+}
+
+// LoadBaseline reads results written by SaveBaseline.
+func LoadBaseline(path string) (BenchmarkResults, error) {
+	var r BenchmarkResults
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// CompareOption configures CompareBenchmarks.
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	alpha     float64 // p-value below which a change is significant
+	threshold float64 // relative slowdown above which a significant change is a regression
+}
+
+func newCompareOptions(opts []CompareOption) compareOptions {
+	o := compareOptions{alpha: defaultSignificanceLevel, threshold: defaultRegressionThreshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSignificanceLevel sets the p-value below which a change in time is
+// taken to be real rather than noise. The default is 0.05.
+func WithSignificanceLevel(alpha float64) CompareOption {
+	return func(o *compareOptions) {
+		o.alpha = alpha
+	}
+}
+
+// WithRegressionThreshold sets how much slower, relative to the baseline's
+// median, an algorithm must significantly be to regress. The default is 0.10,
+// ten percent.
+func WithRegressionThreshold(threshold float64) CompareOption {
+	return func(o *compareOptions) {
+		o.threshold = threshold
+	}
+}
+
+// BenchmarkDelta compares one cell of a baseline run with the same cell of a
+// later run.
+type BenchmarkDelta struct {
+	Algorithm    string
+	Distribution InputDistribution
+	Size         int
+	Old, New     time.Duration // median times
+	OldN, NewN   int           // trials
+	// Delta is the relative change of the median time, (New - Old) / Old.
+	Delta float64
+	// P is the two-sided p-value of a Mann-Whitney U test of the trials' times:
+	// the probability of the runs differing at least this much were both drawn
+	// from the same distribution.
+	P           float64
+	Significant bool // P is below the significance level
+	// Regression is set for a significant slowdown beyond the threshold, or
+	// for running out of budget. It is never set for the reference algorithm,
+	// which only shows how much the machine's speed changed between the runs.
+	Regression bool
+	Reference  bool
+	// OverBudget is set for a cell the baseline measured but the later run
+	// ran out of time budget on, and only estimated. New is then the
+	// estimate, and NewN and P are zero.
+	OverBudget bool
+}
+
+// BenchmarkComparison is the result of CompareBenchmarks.
+type BenchmarkComparison struct {
+	Alpha, Threshold float64
+	// Deltas are in the order of the later run's cells, with the reference
+	// algorithm's last.
+	Deltas []BenchmarkDelta
+}
+
+// CompareBenchmarks compares every cell of current with the cell of baseline
+// for the same algorithm, distribution and size, the way benchstat does: a
+// change in the median time counts only if a Mann-Whitney U test of the two
+// runs' trials finds it significant. A cell the baseline measured but current
+// ran out of budget on is a regression, however long it took. Other cells in
+// only one of the runs, or estimated in either, are left out. With three
+// trials per run no change can be significant at the default level of 0.05,
+// which Err reports: it takes at least four, and ten or more are better.
+func CompareBenchmarks(baseline, current BenchmarkResults, opts ...CompareOption) BenchmarkComparison {
+	o := newCompareOptions(opts)
+	type cellKey struct {
+		algorithm    string
+		distribution InputDistribution
+		size         int
+	}
+	old := make(map[cellKey]BenchmarkCell)
+	for _, cell := range baseline.Cells {
+		old[cellKey{cell.Algorithm, cell.Distribution, cell.Size}] = cell
+	}
+
+	comparison := BenchmarkComparison{Alpha: o.alpha, Threshold: o.threshold}
+	var references []BenchmarkDelta
+	for _, cell := range current.Cells {
+		base, ok := old[cellKey{cell.Algorithm, cell.Distribution, cell.Size}]
+		if !ok || len(base.Times) == 0 {
+			continue
+		}
+		if cell.Estimated {
+			delta := BenchmarkDelta{
+				Algorithm:    cell.Algorithm,
+				Distribution: cell.Distribution,
+				Size:         cell.Size,
+				Old:          base.Median,
+				New:          cell.Median,
+				OldN:         len(base.Times),
+				Reference:    cell.Algorithm == referenceAlgorithm,
+				OverBudget:   true,
+			}
+			delta.Regression = !delta.Reference
+			if delta.Reference {
+				references = append(references, delta)
+			} else {
+				comparison.Deltas = append(comparison.Deltas, delta)
+			}
+			continue
+		}
+		if len(cell.Times) == 0 {
+			continue
+		}
+		delta := BenchmarkDelta{
+			Algorithm:    cell.Algorithm,
+			Distribution: cell.Distribution,
+			Size:         cell.Size,
+			Old:          base.Median,
+			New:          cell.Median,
+			OldN:         len(base.Times),
+			NewN:         len(cell.Times),
+			P:            mannWhitneyU(base.Times, cell.Times),
+			Reference:    cell.Algorithm == referenceAlgorithm,
+		}
+		if base.Median > 0 {
+			delta.Delta = float64(cell.Median-base.Median) / float64(base.Median)
+		}
+		delta.Significant = delta.P < o.alpha
+		delta.Regression = delta.Significant && !delta.Reference && delta.Delta > o.threshold
+		if delta.Reference {
+			references = append(references, delta)
+		} else {
+			comparison.Deltas = append(comparison.Deltas, delta)
+		}
+	}
+	comparison.Deltas = append(comparison.Deltas, references...)
+	return comparison
+}
+
+// Regressions returns the deltas that regressed.
+func (c BenchmarkComparison) Regressions() []BenchmarkDelta {
+	var regressions []BenchmarkDelta
+	for _, delta := range c.Deltas {
+		if delta.Regression {
+			regressions = append(regressions, delta)
+		}
+	}
+	return regressions
+}
+
+// Err returns nil if nothing regressed, otherwise an error wrapping
+// ErrBenchmarkRegression for every regression. It also wraps ErrTooFewTrials
+// if any cell has too few trials in either run to ever be significant at the
+// comparison's level, since a regression there would go unnoticed.
+func (c BenchmarkComparison) Err() error {
+	var errs []error
+	for _, delta := range c.Deltas {
+		if delta.OverBudget {
+			continue
+		}
+		if p := mannWhitneyMinP(delta.OldN, delta.NewN); p >= c.Alpha {
+			errs = append(errs, fmt.Errorf("%w: with %d+%d trials the smallest p-value is %.3f, not below %.3f",
+				ErrTooFewTrials, delta.OldN, delta.NewN, p, c.Alpha))
+			break
+		}
+	}
+	for _, delta := range c.Regressions() {
+		if delta.OverBudget {
+			errs = append(errs, fmt.Errorf("%w: %s on %s input of size %d: %s -> over budget (estimated %s)",
+				ErrBenchmarkRegression, delta.Algorithm, delta.Distribution, delta.Size,
+				delta.Old, estimatedDuration(delta.New)))
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%w: %s on %s input of size %d: %s -> %s (%+.1f%%, p=%.3f)",
+			ErrBenchmarkRegression, delta.Algorithm, delta.Distribution, delta.Size,
+			delta.Old, delta.New, 100*delta.Delta, delta.P))
+	}
+	return errors.Join(errs...)
+}
+
+// Table renders the comparison as a columnized table in the style of
+// benchstat: insignificant changes are shown as "~", and regressions and the
+// reference rows are flagged.
+func (c BenchmarkComparison) Table() string {
+	rows := []string{"\x1fAlgorithm\x1fDistribution\x1fSize\x1fOld\x1fNew\x1fDelta\x1f\x1f"}
+	for _, delta := range c.Deltas {
+		change := "~"
+		if delta.Significant {
+			change = fmt.Sprintf("%+.1f%%", 100*delta.Delta)
+		}
+		change += fmt.Sprintf(" (p=%.3f n=%d+%d)", delta.P, delta.OldN, delta.NewN)
+		newTime := delta.New.String()
+		if delta.OverBudget {
+			change, newTime = "over budget", estimatedDuration(delta.New)
+		}
+		flag := ""
+		switch {
+		case delta.Regression:
+			flag = "REGRESSION"
+		case delta.Reference:
+			flag = "reference"
+		}
+		rows = append(rows, fmt.Sprintf("\x1f%s\x1f%s\x1f%d\x1f%s\x1f%s\x1f%s\x1f%s\x1f",
+			delta.Algorithm, delta.Distribution, delta.Size, delta.Old, newTime, change, flag))
+	}
+	return columnize.Format(rows, &columnize.Config{Delim: string([]byte{0x1f}), Glue: "  "})
+}
+
+// mannWhitneyU returns the two-sided p-value of a Mann-Whitney U test of
+// whether x and y come from the same distribution. It makes no assumption
+// about the shape of the distribution, which suits timings with their long
+// tails. Small samples without ties get the exact p-value; others the normal
+// approximation, corrected for ties and continuity.
+func mannWhitneyU(x, y []time.Duration) float64 {
+	n1, n2 := len(x), len(y)
+	type sample struct {
+		value time.Duration
+		fromX bool
+	}
+	samples := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		samples = append(samples, sample{v, true})
+	}
+	for _, v := range y {
+		samples = append(samples, sample{v, false})
+	}
+	slices.SortFunc(samples, func(a, b sample) int { return cmp.Compare(a.value, b.value) })
+
+	// Rank the samples from 1, giving tied ones the mean of their ranks.
+	var rankSumX, tieTerm float64
+	ties := false
+	for i := 0; i < len(samples); {
+		j := i + 1
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for _, s := range samples[i:j] {
+			if s.fromX {
+				rankSumX += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+	u := rankSumX - float64(n1*(n1+1))/2
+
+	if !ties && n1 <= exactMannWhitneyMax && n2 <= exactMannWhitneyMax {
+		// U is a whole number without ties. Both tails are summed from the
+		// exact distribution of U, which is symmetric about n1·n2/2.
+		dist := mannWhitneyDistribution(n1, n2)
+		k := int(math.Round(math.Min(u, float64(n1*n2)-u)))
+		var tail float64
+		for _, p := range dist[:k+1] {
+			tail += p
+		}
+		return math.Min(1, 2*tail)
+	}
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := math.Max(0, math.Abs(u-mean)-0.5) / math.Sqrt(variance)
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// mannWhitneyMinP returns the smallest two-sided p-value mannWhitneyU can
+// give for samples of n1 and n2 values: that of every value of one sample
+// being below every value of the other, one of the C(n1+n2, n1) equally
+// likely orders, at either end.
+func mannWhitneyMinP(n1, n2 int) float64 {
+	orders := 1.0
+	for i := 1; i <= n1; i++ {
+		orders = orders * float64(n2+i) / float64(i)
+	}
+	return math.Min(1, 2/orders)
+}
+
+// mannWhitneyDistribution returns the probability of each value 0 to n1·n2 of
+// the U statistic of samples of n1 and n2 values with no ties, when both come
+// from the same distribution. It counts the arrangements of the samples in
+// order that give each U, by adding the samples one at a time: the largest
+// value is either from the first sample, beating all n2 of the second's, or
+// from the second, beating none.
+func mannWhitneyDistribution(n1, n2 int) []float64 {
+	// counts[i][j][u] is the number of arrangements of i and j values with
+	// statistic u, kept only for the current and previous i.
+	prev := make([][]float64, n2+1)
+	for j := range prev {
+		prev[j] = []float64{1}
+	}
+	for i := 1; i <= n1; i++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = []float64{1}
+		for j := 1; j <= n2; j++ {
+			cur[j] = make([]float64, i*j+1)
+			for u, c := range prev[j] { // the largest value is the first sample's
+				cur[j][u+j] += c
+			}
+			for u, c := range cur[j-1] { // the largest value is the second sample's
+				cur[j][u] += c
+			}
+		}
+		prev = cur
+	}
+
+	dist := prev[n2]
+	var total float64
+	for _, c := range dist {
+		total += c
+	}
+	for u := range dist {
+		dist[u] /= total
+	}
+	return dist
+}
+
+// CheckBenchmarkBaseline runs the benchmark recorded in the baseline at path,
+// with the same configuration, compares the run with the baseline and prints
+// the comparison. It returns an error if the baseline cannot be read or the
+// run fails, and otherwise the comparison's Err, so that a caller can exit
+// non-zero on a regression, or when the baseline has too few trials to detect
+// one.
+func CheckBenchmarkBaseline(path string, opts ...CompareOption) error {
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		return err
+	}
+	current, err := RunBenchmark(baseline.Config)
+	if err != nil {
+		return err
+	}
+	comparison := CompareBenchmarks(baseline, current, opts...)
+	fmt.Println(comparison.Table())
+	return comparison.Err()
+}
+
+// TestBenchmarkBaseline saves a small benchmark run as a baseline, checks a
+// second run against it, and then compares the baseline with a run of a
+// deliberately slowed InsertionSort to show a regression being caught.
+func TestBenchmarkBaseline() {
+	cfg := BenchmarkConfig{
+		Sizes:         []int{1000, 4000},
+		Trials:        7,
+		Warmup:        1,
+		Seed:          1,
+		Distributions: []InputDistribution{UniformInput},
+		Algorithms:    []string{"InsertionSort", "MergeSort", "PdqSort"},
+	}
+	baseline, err := RunBenchmark(cfg)
+	if err != nil {
+		fmt.Println("Benchmark:", err)
+		return
+	}
+	dir, err := os.MkdirTemp("", "benchmark-baseline-*")
+	if err != nil {
+		fmt.Println("Baseline:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "baseline.json")
+	if err := baseline.SaveBaseline(path); err != nil {
+		fmt.Println("Baseline:", err)
+		return
+	}
+
+	fmt.Println("Unchanged code:")
+	fmt.Println("Error:", CheckBenchmarkBaseline(path))
+	fmt.Println()
+
+	// Sort every InsertionSort input twice, doubling its time.
+	slowed := baseline
+	slowed.Cells = nil
+	for _, cell := range baseline.Cells {
+		if cell.Algorithm == "InsertionSort" {
+			input := cell.Distribution.Generate(cell.Size, rand.New(rand.NewSource(inputSeed(cfg.Seed, cell.Distribution, cell.Size))))
+			cell.Times = nil
+			for i := 0; i < cfg.Trials; i++ {
+				cell.Times = append(cell.Times, benchmarkSortAlgorithm(input, func(arr []int) {
+					InsertionSort(slices.Clone(arr))
+					InsertionSort(arr)
+				}))
+			}
+			cell.Min, cell.Median, cell.Mean, cell.StdDev = durationStats(cell.Times)
+		}
+		slowed.Cells = append(slowed.Cells, cell)
+	}
+	fmt.Println("InsertionSort slowed down:")
+	comparison := CompareBenchmarks(baseline, slowed)
+	fmt.Println(comparison.Table())
+	fmt.Println("Error:", comparison.Err())
+}
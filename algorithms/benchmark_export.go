@@ -0,0 +1,319 @@
+package algorithms
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WriteCSV writes one row per cell, after a header row. Times are in
+// nanoseconds, and the operation count columns are empty for algorithms that
+// are not instrumented. The memory columns are in bytes and heap objects.
+// Estimated cells have only a median time, and true in the estimated column.
+func (r BenchmarkResults) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"algorithm", "distribution", "size", "trials", "min_ns", "median_ns", "mean_ns", "stddev_ns",
+		"comparisons", "swaps", "moves", "allocations", "allocated_elements", "max_depth",
+		"bytes_allocated", "heap_allocations", "peak_heap_bytes", "estimated",
+	})
+	for _, cell := range r.Cells {
+		record := []string{
+			cell.Algorithm, cell.Distribution.String(), strconv.Itoa(cell.Size), strconv.Itoa(len(cell.Times)),
+			strconv.FormatInt(int64(cell.Min), 10), strconv.FormatInt(int64(cell.Median), 10),
+			strconv.FormatInt(int64(cell.Mean), 10), strconv.FormatInt(int64(cell.StdDev), 10),
+		}
+		if cell.Estimated {
+			record[4], record[6], record[7] = "", "", ""
+		}
+		if c := cell.Counts; c != nil {
+			record = append(record,
+				strconv.FormatInt(c.Comparisons, 10), strconv.FormatInt(c.Swaps, 10), strconv.FormatInt(c.Moves, 10),
+				strconv.FormatInt(c.Allocations, 10), strconv.FormatInt(c.AllocatedElements, 10), strconv.Itoa(c.MaxDepth))
+		} else {
+			record = append(record, "", "", "", "", "", "")
+		}
+		if m := cell.Memory; m != nil {
+			record = append(record, strconv.FormatUint(m.BytesAllocated, 10), strconv.FormatUint(m.Allocations, 10),
+				strconv.FormatUint(m.PeakHeap, 10))
+		} else {
+			record = append(record, "", "", "")
+		}
+		cw.Write(append(record, strconv.FormatBool(cell.Estimated)))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the results, configuration included, as indented JSON.
+// Times are in nanoseconds.
+func (r BenchmarkResults) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the results as a Markdown table, one row per cell.
+// Estimated times are marked with a "≈" and explained below the table.
+func (r BenchmarkResults) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Algorithm | Distribution | Size | Min | Median | Mean | StdDev | Comparisons | Swaps | Moves | Allocs | Bytes Allocated | Heap Allocs | Peak Heap |\n")
+	b.WriteString("|---|---|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|--:|\n")
+	estimated := false
+	for _, cell := range r.Cells {
+		if cell.Estimated {
+			estimated = true
+			fmt.Fprintf(&b, "| %s | %s | %d | - | %s | - | - | - | - | - | - | - | - | - |\n",
+				cell.Algorithm, cell.Distribution, cell.Size, estimatedDuration(cell.Median))
+			continue
+		}
+		counts := "- | - | - | -"
+		if c := cell.Counts; c != nil {
+			counts = fmt.Sprintf("%d | %d | %d | %d", c.Comparisons, c.Swaps, c.Moves, c.Allocations)
+		}
+		memory := "- | - | -"
+		if m := cell.Memory; m != nil {
+			memory = fmt.Sprintf("%s | %d | %s", formatBytes(m.BytesAllocated), m.Allocations, formatBytes(m.PeakHeap))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %s | %s | %s | %s | %s |\n",
+			cell.Algorithm, cell.Distribution, cell.Size, cell.Min, cell.Median, cell.Mean, cell.StdDev, counts, memory)
+	}
+	if estimated {
+		b.WriteString("\n≈ marks times estimated from the smaller sizes, for cells beyond the benchmark's time budget.\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// chartPalette colors the lines of WriteSVG's chart, and chartDashes varies
+// their stroke once the colors run out.
+var (
+	chartPalette = []string{
+		"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b",
+		"#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+	}
+	chartDashes = []string{"", "6 3", "2 2"}
+)
+
+const (
+	chartWidth, chartHeight = 960, 540
+	chartLeft, chartRight   = 80, 200 // the right margin holds the legend
+	chartTop, chartBottom   = 50, 60
+)
+
+// WriteSVG writes a self-contained SVG line chart of the median time against
+// input size for every algorithm on the given distribution. Both axes are
+// logarithmic, so that algorithms of very different speeds share one chart
+// and a line's slope shows its growth rate: 1 for n, 2 for n². Estimated
+// times are drawn as hollow points on a dotted line.
+func (r BenchmarkResults) WriteSVG(w io.Writer, dist InputDistribution) error {
+	type point struct {
+		size, median float64
+		estimated    bool
+	}
+	series := make(map[string][]point)
+	var names []string
+	var sizes []int
+	minTime, maxTime := math.Inf(1), math.Inf(-1)
+	for _, cell := range r.Cells {
+		if cell.Distribution != dist || cell.Size < 1 || cell.Median <= 0 {
+			continue
+		}
+		if _, ok := series[cell.Algorithm]; !ok {
+			names = append(names, cell.Algorithm)
+		}
+		series[cell.Algorithm] = append(series[cell.Algorithm], point{float64(cell.Size), float64(cell.Median), cell.Estimated})
+		if !slices.Contains(sizes, cell.Size) {
+			sizes = append(sizes, cell.Size)
+		}
+		minTime, maxTime = min(minTime, float64(cell.Median)), max(maxTime, float64(cell.Median))
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no results for the %s distribution", dist)
+	}
+	slices.Sort(sizes)
+
+	// Scale both axes in log10, with the time axis widened to whole decades.
+	minX, maxX := math.Log10(float64(sizes[0])), math.Log10(float64(sizes[len(sizes)-1]))
+	if minX == maxX {
+		minX, maxX = minX-0.5, maxX+0.5
+	}
+	minY, maxY := math.Floor(math.Log10(minTime)), math.Ceil(math.Log10(maxTime))
+	if minY == maxY {
+		maxY++
+	}
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	plotHeight := float64(chartHeight - chartTop - chartBottom)
+	x := func(size float64) float64 {
+		return chartLeft + (math.Log10(size)-minX)/(maxX-minX)*plotWidth
+	}
+	y := func(ns float64) float64 {
+		return chartTop + (maxY-math.Log10(ns))/(maxY-minY)*plotHeight
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`+"\n", chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="16" text-anchor="middle">Median sort time vs input size (%s input)</text>`+"\n",
+		chartLeft+int(plotWidth)/2, chartTop/2+5, html.EscapeString(dist.String()))
+
+	// Grid lines and tick labels: every power of ten on the time axis, every
+	// measured size on the size axis.
+	for decade := minY; decade <= maxY; decade++ {
+		ty := y(math.Pow(10, decade))
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", chartLeft, ty, chartLeft+plotWidth, ty)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`+"\n", chartLeft-6, ty+4, formatNanos(math.Pow(10, decade)))
+	}
+	for _, size := range sizes {
+		tx := x(float64(size))
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.1f" stroke="#eee"/>`+"\n", tx, chartTop, tx, chartTop+plotHeight)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%d</text>`+"\n", tx, chartTop+plotHeight+18, size)
+	}
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%.1f" fill="none" stroke="#333"/>`+"\n", chartLeft, chartTop, plotWidth, plotHeight)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">input size (log scale)</text>`+"\n", chartLeft+plotWidth/2, chartHeight-15)
+	fmt.Fprintf(&b, `<text x="20" y="%.1f" text-anchor="middle" transform="rotate(-90 20 %.1f)">median time (log scale)</text>`+"\n",
+		chartTop+plotHeight/2, chartTop+plotHeight/2)
+
+	estimated := false
+	for i, name := range names {
+		color := chartPalette[i%len(chartPalette)]
+		dash := chartDashes[i/len(chartPalette)%len(chartDashes)]
+		// The measured points, then the estimated ones continuing from the last
+		// measured point; estimates only ever follow measurements.
+		var measured, estimates []string
+		for _, p := range series[name] {
+			pt := fmt.Sprintf("%.1f,%.1f", x(p.size), y(p.median))
+			if !p.estimated {
+				measured = append(measured, pt)
+				continue
+			}
+			if len(estimates) == 0 && len(measured) > 0 {
+				estimates = append(estimates, measured[len(measured)-1])
+			}
+			estimates = append(estimates, pt)
+			estimated = true
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" stroke-dasharray="%s" points="%s"/>`+"\n",
+			color, dash, strings.Join(measured, " "))
+		if len(estimates) > 1 {
+			fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" stroke-dasharray="1 4" stroke-linecap="round" points="%s"/>`+"\n",
+				color, strings.Join(estimates, " "))
+		}
+		for _, pt := range measured {
+			cx, cy, _ := strings.Cut(pt, ",")
+			fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="3" fill="%s"/>`+"\n", cx, cy, color)
+		}
+		if len(estimates) > 0 && len(measured) > 0 {
+			estimates = estimates[1:] // the bridge from the last measured point
+		}
+		for _, pt := range estimates {
+			cx, cy, _ := strings.Cut(pt, ",")
+			fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="3" fill="white" stroke="%s"/>`+"\n", cx, cy, color)
+		}
+
+		// Legend entry.
+		ly := chartTop + 10 + i*18
+		lx := chartWidth - chartRight + 15
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2" stroke-dasharray="%s"/>`+"\n",
+			lx, ly, lx+24, ly, color, dash)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", lx+30, ly+4, html.EscapeString(name))
+	}
+	if estimated {
+		ly := chartTop + 10 + len(names)*18 + 10
+		lx := chartWidth - chartRight + 15
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333" stroke-width="2" stroke-dasharray="1 4" stroke-linecap="round"/>`+"\n",
+			lx, ly, lx+24, ly)
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="3" fill="white" stroke="#333"/>`+"\n", lx+24, ly)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">estimated</text>`+"\n", lx+30, ly+4)
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatNanos formats a power of ten nanoseconds with the largest unit that
+// keeps it whole, e.g. 1ms rather than 1e+06ns.
+func formatNanos(ns float64) string {
+	for _, unit := range []struct {
+		name string
+		size float64
+	}{{"s", 1e9}, {"ms", 1e6}, {"µs", 1e3}} {
+		if ns >= unit.size {
+			return strconv.FormatFloat(ns/unit.size, 'f', -1, 64) + unit.name
+		}
+	}
+	return strconv.FormatFloat(ns, 'f', -1, 64) + "ns"
+}
+
+// Export writes the results to dir as results.csv, results.json and
+// results.md, plus a chart time-<distribution>.svg for every distribution,
+// creating dir if needed.
+func (r BenchmarkResults) Export(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	write := func(name string, writeTo func(io.Writer) error) (err error) {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		return writeTo(f)
+	}
+
+	errs := []error{
+		write("results.csv", r.WriteCSV),
+		write("results.json", r.WriteJSON),
+		write("results.md", r.WriteMarkdown),
+	}
+	for _, dist := range r.Config.Distributions {
+		errs = append(errs, write("time-"+dist.String()+".svg", func(w io.Writer) error { return r.WriteSVG(w, dist) }))
+	}
+	return errors.Join(errs...)
+}
+
+// TestBenchmarkExport runs a small benchmark, prints it as Markdown and
+// exports it in every format to a temporary directory.
+func TestBenchmarkExport() {
+	results, err := RunBenchmark(BenchmarkConfig{
+		Sizes:         []int{1000, 4000, 16000},
+		Trials:        3,
+		Seed:          1,
+		Distributions: []InputDistribution{UniformInput, SortedInput},
+		Algorithms:    []string{"InsertionSort", "MergeSort", "TimSort", "RadixSort"},
+	})
+	if err != nil {
+		fmt.Println("Benchmark:", err)
+		return
+	}
+	results.WriteMarkdown(os.Stdout)
+
+	dir, err := os.MkdirTemp("", "benchmark-export-*")
+	if err != nil {
+		fmt.Println("Export:", err)
+		return
+	}
+	if err := results.Export(dir); err != nil {
+		fmt.Println("Export:", err)
+		return
+	}
+	entries, _ := os.ReadDir(dir)
+	fmt.Println("Exported to", dir+":")
+	for _, entry := range entries {
+		fmt.Println("  ·", entry.Name())
+	}
+}
@@ -0,0 +1,304 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ryanuber/columnize"
+)
+
+const (
+	// minFitSizes is the number of input sizes a fit needs; with fewer, every
+	// model fits about equally well.
+	minFitSizes = 3
+	// fitMargin is how much higher a faster growing model's R² must be to be
+	// preferred over a slower growing one, so that noise alone does not make
+	// n log n look like n log² n.
+	fitMargin = 0.002
+)
+
+// ComplexityClass is a candidate growth rate for a sort's running time.
+type ComplexityClass int
+
+const (
+	UnknownComplexity ComplexityClass = iota
+	ComplexityN                       // n
+	ComplexityNK                      // nk, for k the number of digits in the input's range
+	ComplexityNLogN                   // n log n
+	ComplexityNLog2N                  // n log² n
+	ComplexityN2                      // n²
+)
+
+func (c ComplexityClass) String() string {
+	switch c {
+	case ComplexityN:
+		return "n"
+	case ComplexityNK:
+		return "nk"
+	case ComplexityNLogN:
+		return "nlogn"
+	case ComplexityNLog2N:
+		return "nlog²n"
+	case ComplexityN2:
+		return "n²"
+	default:
+		return "?"
+	}
+}
+
+// rank orders the classes by growth. ComplexityNK ranks with ComplexityN: on
+// inputs of a fixed range k is a constant, and the two can not be told apart.
+func (c ComplexityClass) rank() int {
+	switch c {
+	case ComplexityN, ComplexityNK:
+		return 1
+	case ComplexityNLogN:
+		return 2
+	case ComplexityNLog2N:
+		return 3
+	case ComplexityN2:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// eval returns the model's value for n elements with k digits in their range.
+func (c ComplexityClass) eval(n, k int) float64 {
+	x := float64(n)
+	logN := math.Log2(max(x, 2))
+	switch c {
+	case ComplexityN:
+		return x
+	case ComplexityNK:
+		return x * float64(k)
+	case ComplexityNLogN:
+		return x * logN
+	case ComplexityNLog2N:
+		return x * logN * logN
+	case ComplexityN2:
+		return x * x
+	default:
+		return math.NaN()
+	}
+}
+
+// parseComplexity reads the class out of a TimeComplexity entry such as
+// "θ(nlogn)", using the first parenthesized expression. An O(n+k) sort is
+// linear for a fixed range, so it counts as ComplexityN.
+func parseComplexity(s string) ComplexityClass {
+	_, expr, ok := strings.Cut(s, "(")
+	if !ok {
+		return UnknownComplexity
+	}
+	expr, _, _ = strings.Cut(expr, ")")
+	switch strings.ReplaceAll(expr, " ", "") {
+	case "n", "n+k":
+		return ComplexityN
+	case "nk":
+		return ComplexityNK
+	case "nlogn":
+		return ComplexityNLogN
+	case "nlog²n":
+		return ComplexityNLog2N
+	case "n²":
+		return ComplexityN2
+	default:
+		return UnknownComplexity
+	}
+}
+
+// ComplexityFit is the best fit of a series of measurements to a ComplexityClass.
+type ComplexityFit struct {
+	Class ComplexityClass
+	// Coefficient is c in measurement ≈ c · model(n), in the measurement's unit:
+	// nanoseconds for times, operations for counts.
+	Coefficient float64
+	// R2 is the coefficient of determination of the fit in log space; 1 is a
+	// perfect fit.
+	R2 float64
+}
+
+func (f ComplexityFit) String() string {
+	return fmt.Sprintf("%s (R² %.4f)", f.Class, f.R2)
+}
+
+// ComplexityReport compares the growth of one algorithm's measurements on one
+// input distribution with its declared complexity.
+type ComplexityReport struct {
+	Algorithm    string
+	Distribution InputDistribution
+	// Time is the best fit of the median times, and Ops of the sum of
+	// comparisons, swaps and moves; Ops is nil if the algorithm is not instrumented.
+	Time ComplexityFit
+	Ops  *ComplexityFit
+	// Best, Avg and Worst are the declared classes, parsed from the
+	// algorithm's TimeComplexity.
+	Best, Avg, Worst ComplexityClass
+	// Mismatch is set when the fitted class, of Ops if available and of Time
+	// otherwise, is outside the declared best to worst case range, or, on
+	// uniformly random input, differs from the declared average case.
+	Mismatch bool
+}
+
+// fitComplexity fits values, measured at sizes whose ranges have ks digits, to
+// each of classes and returns the best fit. Each model is fitted as
+// log(value) = log(c) + log(model(n)), so every size weighs the same however
+// large its values, and fits are ranked by R² in log space. A class must beat
+// the earlier ones by fitMargin, so list slower growing ones first.
+func fitComplexity(sizes, ks []int, values []float64, classes []ComplexityClass) ComplexityFit {
+	var best ComplexityFit
+	for _, class := range classes {
+		var logC, meanLogY float64
+		for i, n := range sizes {
+			logC += math.Log(values[i]) - math.Log(class.eval(n, ks[i]))
+			meanLogY += math.Log(values[i])
+		}
+		logC /= float64(len(sizes))
+		meanLogY /= float64(len(sizes))
+
+		var ssRes, ssTot float64
+		for i, n := range sizes {
+			logY := math.Log(values[i])
+			residual := logY - logC - math.Log(class.eval(n, ks[i]))
+			ssRes += residual * residual
+			ssTot += (logY - meanLogY) * (logY - meanLogY)
+		}
+		r2 := 1.0
+		if ssTot > 0 {
+			r2 = 1 - ssRes/ssTot
+		}
+		if best.Class == UnknownComplexity || r2 > best.R2+fitMargin {
+			best = ComplexityFit{Class: class, Coefficient: math.Exp(logC), R2: r2}
+		}
+	}
+	return best
+}
+
+// FitComplexity fits each algorithm's measurements on each distribution
+// across the input sizes to the candidate models n, n log n, n log² n and n²,
+// plus nk for algorithms that declare it, and checks the best fit against the
+// declared complexity. Sizes below 2, measurements of zero and estimated cells
+// are left out, and series with fewer than three sizes left are not reported.
+func (r BenchmarkResults) FitComplexity() []ComplexityReport {
+	algos, _ := r.Config.algorithms()
+	var reports []ComplexityReport
+	for _, algo := range algos {
+		report := ComplexityReport{
+			Algorithm: algo.Name,
+			Best:      parseComplexity(algo.TimeComplexity["Best Case"]),
+			Avg:       parseComplexity(algo.TimeComplexity["Avg Case"]),
+			Worst:     parseComplexity(algo.TimeComplexity["Worst Case"]),
+		}
+		classes := candidateClasses(report.Best, report.Avg, report.Worst)
+
+		for _, dist := range r.Config.Distributions {
+			var sizes, ks, opsSizes, opsKs []int
+			var times, ops []float64
+			for _, cell := range r.Cells {
+				if cell.Algorithm != algo.Name || cell.Distribution != dist || cell.Size < 2 || cell.Estimated {
+					continue
+				}
+				if cell.Median > 0 {
+					sizes, ks = append(sizes, cell.Size), append(ks, cell.RangeDigits)
+					times = append(times, float64(cell.Median))
+				}
+				if c := cell.Counts; c != nil && c.Comparisons+c.Swaps+c.Moves > 0 {
+					opsSizes, opsKs = append(opsSizes, cell.Size), append(opsKs, cell.RangeDigits)
+					ops = append(ops, float64(c.Comparisons+c.Swaps+c.Moves))
+				}
+			}
+			if len(sizes) < minFitSizes {
+				continue
+			}
+
+			report := report
+			report.Distribution = dist
+			report.Time = fitComplexity(sizes, ks, times, classes)
+			fitted := report.Time.Class
+			if len(ops) >= minFitSizes {
+				fit := fitComplexity(opsSizes, opsKs, ops, classes)
+				report.Ops = &fit
+				fitted = fit.Class
+			}
+			report.Mismatch = complexityMismatch(fitted, report.Best, report.Avg, report.Worst, dist)
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// candidateClasses returns the classes to fit an algorithm with the declared
+// classes to, slowest growing first: nk is only a candidate for algorithms
+// that declare it.
+func candidateClasses(best, avg, worst ComplexityClass) []ComplexityClass {
+	if best == ComplexityNK || avg == ComplexityNK || worst == ComplexityNK {
+		return []ComplexityClass{ComplexityN, ComplexityNK, ComplexityNLogN, ComplexityNLog2N, ComplexityN2}
+	}
+	return []ComplexityClass{ComplexityN, ComplexityNLogN, ComplexityNLog2N, ComplexityN2}
+}
+
+// extrapolateTime estimates algo's median time at size, with k digits in the
+// input's range, from its measured cells of one distribution: from the best
+// fitting class if there are enough of them, and otherwise from the declared
+// average case, scaled to fit the few there are. It reports false if there is
+// nothing to extrapolate from.
+func extrapolateTime(algo SortingAlgorithm, cells []BenchmarkCell, size, k int) (time.Duration, bool) {
+	var sizes, ks []int
+	var times []float64
+	for _, cell := range cells {
+		if cell.Estimated || cell.Size < 2 || cell.Median <= 0 {
+			continue
+		}
+		sizes, ks = append(sizes, cell.Size), append(ks, cell.RangeDigits)
+		times = append(times, float64(cell.Median))
+	}
+	best := parseComplexity(algo.TimeComplexity["Best Case"])
+	avg := parseComplexity(algo.TimeComplexity["Avg Case"])
+	worst := parseComplexity(algo.TimeComplexity["Worst Case"])
+	classes := candidateClasses(best, avg, worst)
+	if len(sizes) < minFitSizes {
+		if avg == UnknownComplexity {
+			return 0, false
+		}
+		classes = []ComplexityClass{avg}
+	}
+	if len(sizes) == 0 {
+		return 0, false
+	}
+	fit := fitComplexity(sizes, ks, times, classes)
+	return time.Duration(fit.Coefficient * fit.Class.eval(size, k)), true
+}
+
+// complexityMismatch reports whether fitted contradicts the declared classes,
+// ignoring any that are unknown.
+func complexityMismatch(fitted, best, avg, worst ComplexityClass, dist InputDistribution) bool {
+	if best != UnknownComplexity && fitted.rank() < best.rank() {
+		return true
+	}
+	if worst != UnknownComplexity && fitted.rank() > worst.rank() {
+		return true
+	}
+	return dist == UniformInput && avg != UnknownComplexity && fitted.rank() != avg.rank()
+}
+
+// ComplexityTable renders the reports as a columnized table, flagging every
+// mismatch with the declared complexity.
+func ComplexityTable(reports []ComplexityReport) string {
+	rows := []string{"\x1fAlgorithm\x1fDistribution\x1fDeclared (best/avg/worst)\x1fTime Fit\x1fOps Fit\x1f\x1f"}
+	for _, report := range reports {
+		ops := "-"
+		if report.Ops != nil {
+			ops = report.Ops.String()
+		}
+		flag := ""
+		if report.Mismatch {
+			flag = "MISMATCH"
+		}
+		rows = append(rows, fmt.Sprintf("\x1f%s\x1f%s\x1f%s / %s / %s\x1f%s\x1f%s\x1f%s\x1f",
+			report.Algorithm, report.Distribution, report.Best, report.Avg, report.Worst, report.Time, ops, flag))
+	}
+	return columnize.Format(rows, &columnize.Config{Delim: string([]byte{0x1f}), Glue: "  "})
+}
@@ -0,0 +1,131 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	// benchmarkValueRange bounds the values of uniformly random inputs.
+	benchmarkValueRange = 1000000
+	// fewUniqueValues is the number of distinct values in FewUniqueInput.
+	fewUniqueValues = 10
+)
+
+// InputDistribution is a shape of benchmark input. Different shapes bring out
+// the best and worst cases of different algorithms: sorted input is the best
+// case for InsertionSort and TimSort, while organ pipe input defeats QuickSort's
+// middle pivot.
+type InputDistribution int
+
+const (
+	UniformInput      InputDistribution = iota // values drawn uniformly from [0, 1000000)
+	SortedInput                                // 0, 1, ..., n-1
+	ReversedInput                              // n, n-1, ..., 1
+	NearlySortedInput                          // sorted, then n/100 random pairs swapped
+	SawtoothInput                              // ascending runs of length √n: 0, 1, ..., √n-1, 0, 1, ...
+	FewUniqueInput                             // values drawn uniformly from [0, 10)
+	OrganPipeInput                             // ascending to the middle, then descending
+	GaussianInput                              // normally distributed around 0, with stddev 125000
+)
+
+// InputDistributions returns every InputDistribution.
+func InputDistributions() []InputDistribution {
+	return []InputDistribution{
+		UniformInput, SortedInput, ReversedInput, NearlySortedInput,
+		SawtoothInput, FewUniqueInput, OrganPipeInput, GaussianInput,
+	}
+}
+
+func (d InputDistribution) String() string {
+	switch d {
+	case UniformInput:
+		return "uniform"
+	case SortedInput:
+		return "sorted"
+	case ReversedInput:
+		return "reversed"
+	case NearlySortedInput:
+		return "nearly-sorted"
+	case SawtoothInput:
+		return "sawtooth"
+	case FewUniqueInput:
+		return "few-unique"
+	case OrganPipeInput:
+		return "organ-pipe"
+	case GaussianInput:
+		return "gaussian"
+	default:
+		return fmt.Sprintf("InputDistribution(%d)", int(d))
+	}
+}
+
+// MarshalText encodes d by name, as in JSON benchmark results.
+func (d InputDistribution) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a name written by MarshalText.
+func (d *InputDistribution) UnmarshalText(text []byte) error {
+	for _, dist := range InputDistributions() {
+		if dist.String() == string(text) {
+			*d = dist
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown input distribution %q", text)
+}
+
+// Generate returns an input of n elements with the distribution d, drawing any
+// randomness from rng.
+func (d InputDistribution) Generate(n int, rng *rand.Rand) []int {
+	arr := make([]int, n)
+	switch d {
+	case UniformInput:
+		for i := range arr {
+			arr[i] = rng.Intn(benchmarkValueRange)
+		}
+	case SortedInput, NearlySortedInput:
+		for i := range arr {
+			arr[i] = i
+		}
+		if d == NearlySortedInput && n > 1 {
+			for swaps := max(1, n/100); swaps > 0; swaps-- {
+				i, j := rng.Intn(n), rng.Intn(n)
+				arr[i], arr[j] = arr[j], arr[i]
+			}
+		}
+	case ReversedInput:
+		for i := range arr {
+			arr[i] = n - i
+		}
+	case SawtoothInput:
+		period := max(2, int(math.Sqrt(float64(n))))
+		for i := range arr {
+			arr[i] = i % period
+		}
+	case FewUniqueInput:
+		for i := range arr {
+			arr[i] = rng.Intn(fewUniqueValues)
+		}
+	case OrganPipeInput:
+		for i := range arr {
+			arr[i] = min(i, n-1-i)
+		}
+	case GaussianInput:
+		for i := range arr {
+			arr[i] = int(rng.NormFloat64() * benchmarkValueRange / 8)
+		}
+	default:
+		panic(fmt.Sprintf("algorithms: unknown input distribution %d", int(d)))
+	}
+	return arr
+}
+
+// inputSeed derives the seed of the input for one distribution and size from
+// the benchmark's seed, so every algorithm sorts the same inputs and a cell's
+// input does not depend on which other cells are run.
+func inputSeed(seed int64, d InputDistribution, size int) int64 {
+	return seed*1000003 + int64(d)*7919 + int64(size)*31
+}
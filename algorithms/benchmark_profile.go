@@ -0,0 +1,153 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"time"
+)
+
+// memorySampleInterval is how often the heap is sampled while measuring a
+// sort's memory.
+const memorySampleInterval = time.Millisecond
+
+// MemoryStats is the memory one sort of a cell's input used, read from
+// runtime.MemStats. The stats are process wide, so they are taken in a run of
+// their own, with nothing else sorting.
+type MemoryStats struct {
+	BytesAllocated uint64 `json:"bytes_allocated"` // heap bytes allocated, whether or not freed during the sort
+	Allocations    uint64 `json:"allocations"`     // heap objects allocated
+	// PeakHeap is how far the heap grew above its size before the sort, live
+	// objects and garbage not yet collected alike. It is sampled every
+	// millisecond and once at the end, so a briefer peak can be missed.
+	PeakHeap uint64 `json:"peak_heap_bytes"`
+}
+
+func (m MemoryStats) String() string {
+	return fmt.Sprintf("alloc %s in %d objs | peak %s", formatBytes(m.BytesAllocated), m.Allocations, formatBytes(m.PeakHeap))
+}
+
+// formatBytes formats n bytes with a binary unit, e.g. 1.5MiB.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	size, exp := float64(n)/unit, 0
+	for size >= unit && exp < 3 {
+		size /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", size, "KMGT"[exp])
+}
+
+// measureMemory sorts a copy of input with sort, a function returned by
+// sortContextFunc, and returns the memory the sort used.
+func measureMemory(sort func(arr []int) error, input []int) (MemoryStats, error) {
+	arr := slices.Clone(input)
+
+	// Start the sampler before the first reading, so its own allocations are
+	// not counted.
+	ticker := time.NewTicker(memorySampleInterval)
+	defer ticker.Stop()
+	done := make(chan struct{})
+	peak := make(chan uint64)
+	go func() {
+		var m runtime.MemStats
+		var highest uint64
+		for {
+			select {
+			case <-done:
+				peak <- highest
+				return
+			case <-ticker.C:
+				runtime.ReadMemStats(&m)
+				highest = max(highest, m.HeapAlloc)
+			}
+		}
+	}()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	err := sort(arr)
+	runtime.ReadMemStats(&after)
+	close(done)
+	if err != nil {
+		<-peak
+		return MemoryStats{}, err
+	}
+
+	stats := MemoryStats{
+		BytesAllocated: after.TotalAlloc - before.TotalAlloc,
+		Allocations:    after.Mallocs - before.Mallocs,
+	}
+	if highest := max(<-peak, after.HeapAlloc); highest > before.HeapAlloc {
+		stats.PeakHeap = highest - before.HeapAlloc
+	}
+	return stats, nil
+}
+
+// startProfiles starts a CPU profile of the algorithm named name, written to
+// <name>.cpu.pprof in dir, and returns a function that stops it and writes a
+// heap profile to <name>.heap.pprof. Heap profiles accumulate allocations
+// from the start of the program, so pass the previous algorithm's profile to
+// pprof's -base flag to see one algorithm's alone.
+func startProfiles(dir, name string) (stop func() error, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	cpu, err := os.Create(filepath.Join(dir, name+".cpu.pprof"))
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, fmt.Errorf("profiling %s: %w", name, err)
+	}
+	return func() error {
+		pprof.StopCPUProfile()
+		errs := []error{cpu.Close()}
+		heap, err := os.Create(filepath.Join(dir, name+".heap.pprof"))
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		runtime.GC() // bring the in-use figures up to date
+		errs = append(errs, pprof.WriteHeapProfile(heap), heap.Close())
+		return errors.Join(errs...)
+	}, nil
+}
+
+// TestBenchmarkMemory runs a small benchmark of sorts that allocate very
+// differently, prints each cell's memory use, and writes CPU and heap profiles
+// to a temporary directory.
+func TestBenchmarkMemory() {
+	dir, err := os.MkdirTemp("", "benchmark-profiles-*")
+	if err != nil {
+		fmt.Println("Profiles:", err)
+		return
+	}
+	results, err := RunBenchmark(BenchmarkConfig{
+		Sizes:         []int{1000, 10000, 100000},
+		Trials:        3,
+		Seed:          1,
+		Distributions: []InputDistribution{UniformInput},
+		Algorithms:    []string{"MergeSort", "RadixSort", "HeapSort", "PdqSort"},
+		ProfileDir:    dir,
+	})
+	if err != nil {
+		fmt.Println("Benchmark:", err)
+		return
+	}
+	results.Print()
+
+	entries, _ := os.ReadDir(dir)
+	fmt.Println("Profiles in", dir+":")
+	for _, entry := range entries {
+		fmt.Println("  ·", entry.Name())
+	}
+}
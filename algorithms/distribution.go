@@ -0,0 +1,376 @@
+package algorithms
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// countingSortMaxRange is the widest range of values CountingSort tallies
+// directly. Wider ranges would need a huge count array, so they are sorted
+// with LSDRadixSort instead.
+const countingSortMaxRange = 1 << 20
+
+// CountingSort
+// Counting sort tallies how many times each value occurs, then writes the values
+// back in order. It makes no comparisons:
+//   - one pass finds the smallest and largest values, lo and hi
+//   - one pass counts each value in an array of hi - lo + 1 slots
+//   - one pass over the counts rewrites the input
+//
+// That is O(N + K) time and O(K) memory for a range of K values, which beats any
+// comparison sort when K is small compared to N. Ranges wider than
+// countingSortMaxRange fall back to LSDRadixSort.
+func CountingSort(arr []int) {
+	countingSort(&sorter[int]{}, arr)
+}
+
+func countingSort(s *sorter[int], arr []int) {
+	if len(arr) < 2 {
+		return
+	}
+	lo, hi := arr[0], arr[0]
+	for _, num := range arr[1:] {
+		lo = min(lo, num)
+		hi = max(hi, num)
+	}
+	// Subtract as unsigned numbers, which is exact even for math.MinInt to math.MaxInt.
+	span := uint64(hi) - uint64(lo)
+	if span >= countingSortMaxRange {
+		radixSortBase(s, arr, 256)
+		return
+	}
+
+	counts := makeBuffer(s, int(span)+1)
+	s.checkCanceled()
+	for _, num := range arr {
+		counts[num-lo]++
+	}
+	s.checkCanceled()
+	i := 0
+	for offset, count := range counts {
+		for ; count > 0; count-- {
+			arr[i] = lo + offset
+			i++
+		}
+	}
+	s.moved(i)
+}
+
+// LSDRadixSort is RadixSortBase with base 256: each pass sorts by one byte of
+// the values, so 64-bit integers take at most eight passes.
+func LSDRadixSort(arr []int) {
+	RadixSortBase(arr, 256)
+}
+
+func lsdRadixSort(s *sorter[int], arr []int) {
+	radixSortBase(s, arr, 256)
+}
+
+// RadixSortBase
+// A least significant digit radix sort in the given base, which must be at least 2:
+//   - values are mapped to unsigned keys that keep their order, by flipping the sign bit
+//     and subtracting the smallest key, so negative numbers need no special case and
+//     only the digits of the range max - min are ever visited
+//   - each pass counts how many keys have each digit, turns the counts into start
+//     positions with a prefix sum, and copies every element straight to its position
+//     in a buffer; the buffer and the input then swap roles for the next pass
+//   - a pass in which every key has the same digit is skipped
+//
+// Each pass is stable, so after the pass on the most significant digit the
+// values are sorted. With K digits in the range that is O(K·(N + base)) time,
+// with one buffer of N elements and one of base counts. Powers of two are the
+// fastest bases, since digits come from shifts and masks instead of divisions.
+func RadixSortBase(arr []int, base int) {
+	radixSortBase(&sorter[int]{}, arr, base)
+}
+
+// radixKey maps num to an unsigned key in the same order.
+func radixKey(num int) uint64 {
+	return uint64(num) ^ 1<<63
+}
+
+func radixSortBase(s *sorter[int], arr []int, base int) {
+	if base < 2 {
+		panic(fmt.Sprintf("algorithms: radix sort base %d is less than 2", base))
+	}
+	n := len(arr)
+	if n < 2 {
+		return
+	}
+	minKey, maxKey := radixKey(arr[0]), radixKey(arr[0])
+	for _, num := range arr[1:] {
+		minKey = min(minKey, radixKey(num))
+		maxKey = max(maxKey, radixKey(num))
+	}
+	span := maxKey - minKey
+	if span == 0 {
+		return
+	}
+
+	b := uint64(base)
+	pow2 := b&(b-1) == 0
+	shiftPerDigit, mask := uint(bits.TrailingZeros64(b)), b-1
+	counts := make([]int, base)
+	buf := makeBuffer(s, n)
+	s.allocated(base)
+
+	src, dst := arr, buf
+	divisor, shift := uint64(1), uint(0)
+	for {
+		s.checkCanceled()
+		digit := func(num int) uint64 {
+			if pow2 {
+				return (radixKey(num) - minKey) >> shift & mask
+			}
+			return (radixKey(num) - minKey) / divisor % b
+		}
+
+		clear(counts)
+		for _, num := range src {
+			counts[digit(num)]++
+		}
+		if counts[digit(src[0])] != n {
+			// Turn the counts into the index at which each digit's elements start.
+			start := 0
+			for d, count := range counts {
+				counts[d] = start
+				start += count
+			}
+			for _, num := range src {
+				d := digit(num)
+				dst[counts[d]] = num
+				counts[d]++
+			}
+			s.moved(n)
+			src, dst = dst, src
+		}
+
+		// Stop once this was the most significant digit of the span.
+		if span/divisor < b {
+			break
+		}
+		divisor *= b
+		shift += shiftPerDigit
+	}
+	if &src[0] != &arr[0] {
+		s.moved(copy(arr, src))
+	}
+}
+
+// msdInsertionThreshold is the bucket size below which MSDRadixSortStrings
+// switches to InsertionSort, rather than paying for 257 counters per byte.
+const msdInsertionThreshold = 16
+
+// MSDRadixSortStrings
+// A most significant digit radix sort for strings, which orders them byte by
+// byte like strings.Compare:
+//   - the strings are distributed into 257 buckets by their first byte, with strings too
+//     short to have one in a bucket of their own that sorts first
+//   - each bucket of two or more strings is then sorted the same way by the next byte
+//   - buckets of msdInsertionThreshold strings or fewer are finished with InsertionSort
+//
+// Only as many bytes are examined as are needed to tell the strings apart, so
+// strings with long common prefixes cost more than random ones. The sort is
+// stable and uses one auxiliary buffer of N strings for every level.
+func MSDRadixSortStrings(arr []string) {
+	msdRadixSort(&sorter[string]{cmp: strings.Compare}, arr)
+}
+
+func msdRadixSort(s *sorter[string], arr []string) {
+	if len(arr) < 2 {
+		return
+	}
+	msdSortLevel(s, arr, makeBuffer(s, len(arr)), 0)
+}
+
+// msdSortLevel sorts arr, whose strings share their first depth bytes, using
+// aux as scratch space.
+func msdSortLevel(s *sorter[string], arr, aux []string, depth int) {
+	s.enter()
+	defer s.leave()
+	if len(arr) <= msdInsertionThreshold {
+		// Comparing whole strings gives the same order as comparing from depth on.
+		insertionSort(s, arr)
+		return
+	}
+
+	// Bucket 0 holds the strings that end before depth, and bucket c+1 byte c.
+	bucket := func(str string) int {
+		if depth < len(str) {
+			return int(str[depth]) + 1
+		}
+		return 0
+	}
+	// counts[c+1] counts bucket c, so that the prefix sum makes counts[c] its start.
+	var counts [256 + 2]int
+	for _, str := range arr {
+		counts[bucket(str)+1]++
+	}
+	for c := 1; c < len(counts); c++ {
+		counts[c] += counts[c-1]
+	}
+	for _, str := range arr {
+		c := bucket(str)
+		aux[counts[c]] = str
+		counts[c]++
+	}
+	s.moved(2 * copy(arr, aux[:len(arr)]))
+
+	// counts[c] now marks the end of bucket c and so the start of bucket c+1.
+	for c := 1; c <= 256; c++ {
+		if lo, hi := counts[c-1], counts[c]; hi-lo > 1 {
+			msdSortLevel(s, arr[lo:hi], aux, depth+1)
+		}
+	}
+}
+
+// BucketSort
+// Bucket sort for values spread evenly over [0, 1):
+//   - the range is split into N equal buckets and each value is placed in bucket ⌊value·N⌋,
+//     using a prefix sum over the bucket sizes to lay the buckets out in one buffer
+//   - each bucket, holding about one value on average, is sorted with InsertionSort
+//
+// For uniformly distributed input that is O(N) expected time; if the values
+// bunch up into a few buckets it degrades to InsertionSort's O(N²). Values
+// outside [0, 1) still sort correctly, as they are clamped into the first or
+// last bucket. NaNs sort first, as with slices.Sort.
+func BucketSort(arr []float64) {
+	bucketSort(&sorter[float64]{cmp: cmp.Compare[float64]}, arr)
+}
+
+func bucketSort(s *sorter[float64], arr []float64) {
+	n := len(arr)
+	if n < 2 {
+		return
+	}
+	bucket := func(value float64) int {
+		switch {
+		case math.IsNaN(value) || value < 0:
+			return 0
+		case value >= 1:
+			return n - 1
+		}
+		// value·N can round up to N for values just below 1.
+		return min(int(value*float64(n)), n-1)
+	}
+
+	// starts[b] is the index of bucket b's first value, and starts[n] the end.
+	starts := make([]int, n+1)
+	s.allocated(n + 1)
+	for _, value := range arr {
+		starts[bucket(value)+1]++
+	}
+	for b := 1; b <= n; b++ {
+		starts[b] += starts[b-1]
+	}
+	buf := makeBuffer(s, n)
+	next := append([]int(nil), starts[:n]...)
+	s.allocated(n)
+	for _, value := range arr {
+		b := bucket(value)
+		buf[next[b]] = value
+		next[b]++
+	}
+	s.moved(n + copy(arr, buf))
+
+	for b := 0; b < n; b++ {
+		if starts[b+1]-starts[b] > 1 {
+			insertionSort(s, arr[starts[b]:starts[b+1]])
+		}
+	}
+}
+
+// Float64RadixSort
+// An LSD radix sort for float64 values, base 256. IEEE 754 bit patterns of
+// non-negative floats already sort like unsigned integers, and negative ones
+// sort in reverse, so each value is mapped to a key that sorts in numeric order:
+//   - for non-negative values, the sign bit is set
+//   - for negative values, every bit is inverted
+//
+// The keys are sorted in at most eight byte passes and mapped back. -0 sorts
+// before +0, and -Inf and +Inf sort at the ends. NaNs have no place in that
+// order, so they are set aside first and put at the front, as with slices.Sort,
+// keeping their payloads.
+func Float64RadixSort(arr []float64) {
+	float64RadixSort(&sorter[float64]{cmp: cmp.Compare[float64]}, arr)
+}
+
+func float64Key(value float64) uint64 {
+	b := math.Float64bits(value)
+	if b>>63 == 1 {
+		return ^b
+	}
+	return b | 1<<63
+}
+
+func float64FromKey(key uint64) float64 {
+	if key>>63 == 1 {
+		return math.Float64frombits(key &^ (1 << 63))
+	}
+	return math.Float64frombits(^key)
+}
+
+func float64RadixSort(s *sorter[float64], arr []float64) {
+	n := len(arr)
+	if n < 2 {
+		return
+	}
+	keys := make([]uint64, 0, n)
+	s.allocated(n)
+	nans := 0
+	for _, value := range arr {
+		if math.IsNaN(value) {
+			// NaNs are packed at the front as they are found; keys are not
+			// written to arr until every value has been read.
+			arr[nans] = value
+			nans++
+			continue
+		}
+		keys = append(keys, float64Key(value))
+	}
+	s.moved(nans + len(keys))
+
+	keys = radixSortKeys(s, keys)
+	for i, key := range keys {
+		arr[nans+i] = float64FromKey(key)
+	}
+	s.moved(len(keys))
+}
+
+// radixSortKeys sorts keys by byte, least significant first, skipping the
+// bytes every key shares. It returns the sorted keys, which are either keys
+// itself or a buffer of the same length.
+func radixSortKeys[T any](s *sorter[T], keys []uint64) []uint64 {
+	if len(keys) < 2 {
+		return keys
+	}
+	buf := make([]uint64, len(keys))
+	s.allocated(len(keys))
+	src, dst := keys, buf
+	var counts [256]int
+	for shift := uint(0); shift < 64; shift += 8 {
+		clear(counts[:])
+		for _, key := range src {
+			counts[key>>shift&0xff]++
+		}
+		if counts[src[0]>>shift&0xff] == len(src) {
+			continue
+		}
+		start := 0
+		for d, count := range counts {
+			counts[d] = start
+			start += count
+		}
+		for _, key := range src {
+			d := key >> shift & 0xff
+			dst[counts[d]] = key
+			counts[d]++
+		}
+		s.moved(len(src))
+		src, dst = dst, src
+	}
+	return src
+}
@@ -0,0 +1,433 @@
+package algorithms
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+const (
+	defaultExternalMemoryBudget = 64 << 20
+	// externalMinBuffer is the smallest I/O buffer ExternalSort gives a file. A
+	// merge of more runs than the memory budget has room for at this size is
+	// done in several passes.
+	externalMinBuffer = 4 << 10
+	// externalElementSize is the size of an int in memory and in binary files.
+	externalElementSize = 8
+)
+
+// ErrMemoryBudget is returned by ExternalSort when the memory budget is too
+// small to merge two runs.
+var ErrMemoryBudget = errors.New("memory budget too small")
+
+// ExternalFormat is the encoding of the integers in ExternalSort's files.
+type ExternalFormat int
+
+const (
+	// TextFormat is one decimal integer per line. Blank lines and spaces around
+	// the numbers are ignored.
+	TextFormat ExternalFormat = iota
+	// BinaryFormat is a sequence of 64-bit little-endian two's complement integers.
+	BinaryFormat
+)
+
+func (f ExternalFormat) String() string {
+	switch f {
+	case TextFormat:
+		return "text"
+	case BinaryFormat:
+		return "binary"
+	default:
+		return fmt.Sprintf("ExternalFormat(%d)", int(f))
+	}
+}
+
+// ExternalSortOption configures ExternalSort.
+type ExternalSortOption func(*externalSortOptions)
+
+type externalSortOptions struct {
+	format       ExternalFormat
+	memoryBudget int         // bytes of buffers and chunk held at once
+	sortFunc     func([]int) // sorts each chunk in memory
+	tempDir      string      // where runs are spilled; "" means os.TempDir()
+}
+
+func newExternalSortOptions(opts []ExternalSortOption) externalSortOptions {
+	o := externalSortOptions{
+		format:       TextFormat,
+		memoryBudget: defaultExternalMemoryBudget,
+		sortFunc:     PdqSort,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithExternalFormat sets the format of both the input and the output file.
+// The default is TextFormat.
+func WithExternalFormat(format ExternalFormat) ExternalSortOption {
+	return func(o *externalSortOptions) {
+		o.format = format
+	}
+}
+
+// WithMemoryBudget sets roughly how many bytes ExternalSort may hold at once:
+// the chunk being sorted while runs are spilled, and the file buffers while
+// they are merged. The default is 64 MiB.
+func WithMemoryBudget(bytes int) ExternalSortOption {
+	return func(o *externalSortOptions) {
+		o.memoryBudget = bytes
+	}
+}
+
+// WithChunkSort sets the in-memory sort used on each chunk, such as the
+// SortFunc of one of SortingAlgorithms. The default is PdqSort.
+func WithChunkSort(sort func([]int)) ExternalSortOption {
+	return func(o *externalSortOptions) {
+		o.sortFunc = sort
+	}
+}
+
+// WithTempDir sets the directory in which sorted runs are spilled. The default
+// is os.TempDir().
+func WithTempDir(dir string) ExternalSortOption {
+	return func(o *externalSortOptions) {
+		o.tempDir = dir
+	}
+}
+
+// ExternalSortStats describes the work an ExternalSort did.
+type ExternalSortStats struct {
+	Elements    int64 // integers sorted
+	Runs        int   // sorted chunks the input was split into; 0 if it was empty
+	MergePasses int   // passes merging runs; 0 if the input fit in a single chunk
+}
+
+// ExternalSort
+// External merge sort sorts a file of integers too large to fit in memory:
+//   - the input is read in chunks as large as the memory budget allows, and each chunk is
+//     sorted in memory and spilled to a temporary file as a sorted run
+//   - the runs are merged k at a time: each run is read through a buffer, and a
+//     PriorityQueue holding the next integer of every run yields the smallest
+//   - k is as large as the budget allows with a buffer of at least 4 KiB per run and one
+//     for the output; if there are more runs than that, they are merged in several passes
+//
+// With a budget of M bytes and an input of N integers, that is N/(M/8) runs
+// and O(N log N) comparisons in total, and each pass reads and writes the data
+// once. Runs are always spilled in BinaryFormat; the output is written to
+// outputPath in the same format as the input. Temporary files are removed
+// before ExternalSort returns.
+func ExternalSort(inputPath, outputPath string, opts ...ExternalSortOption) (ExternalSortStats, error) {
+	var stats ExternalSortStats
+	o := newExternalSortOptions(opts)
+	if minBudget := 3 * externalMinBuffer; o.memoryBudget < minBudget {
+		return stats, fmt.Errorf("%w: %d bytes, need at least %d", ErrMemoryBudget, o.memoryBudget, minBudget)
+	}
+
+	in, err := os.Open(inputPath)
+	if err != nil {
+		return stats, err
+	}
+	defer in.Close()
+	dir, err := os.MkdirTemp(o.tempDir, "external-sort-*")
+	if err != nil {
+		return stats, err
+	}
+	defer os.RemoveAll(dir)
+
+	runs, err := spillRuns(in, outputPath, dir, o, &stats)
+	if err != nil {
+		return stats, err
+	}
+	if len(runs) == 0 {
+		// The input fit in one chunk, which spillRuns wrote straight to the output.
+		return stats, nil
+	}
+
+	fanIn := o.memoryBudget/externalMinBuffer - 1
+	for pass := 1; len(runs) > fanIn; pass++ {
+		stats.MergePasses++
+		var merged []string
+		for i := 0; i < len(runs); i += fanIn {
+			group := runs[i:min(i+fanIn, len(runs))]
+			if len(group) == 1 {
+				merged = append(merged, group[0])
+				continue
+			}
+			path := filepath.Join(dir, fmt.Sprintf("pass-%d-run-%d", pass, len(merged)))
+			if err := mergeRunFiles(group, path, BinaryFormat, o.memoryBudget); err != nil {
+				return stats, err
+			}
+			for _, run := range group {
+				os.Remove(run)
+			}
+			merged = append(merged, path)
+		}
+		runs = merged
+	}
+	stats.MergePasses++
+	if err := mergeRunFiles(runs, outputPath, o.format, o.memoryBudget); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// spillRuns reads in chunk by chunk, sorts each chunk and writes it to a run
+// file in dir, returning the runs' paths. If the whole input fits in the first
+// chunk, empty or not, it is written to outputPath instead, and no runs are
+// returned.
+func spillRuns(in *os.File, outputPath, dir string, o externalSortOptions, stats *ExternalSortStats) ([]string, error) {
+	// The chunk shares the budget with the input and run buffers.
+	chunk := make([]int, 0, max(1, (o.memoryBudget-2*externalMinBuffer)/externalElementSize))
+	r := newIntReader(in, o.format, externalMinBuffer)
+	var runs []string
+	// next is the integer read past the end of a full chunk, which starts the
+	// following one.
+	var next int
+	hasNext := false
+	for {
+		chunk = chunk[:0]
+		if hasNext {
+			chunk = append(chunk, next)
+		}
+		var err error
+		for len(chunk) < cap(chunk) {
+			var num int
+			if num, err = r.read(); err != nil {
+				break
+			}
+			chunk = append(chunk, num)
+		}
+		if err == nil {
+			// The chunk is full: read one integer more, so that an input that
+			// ends exactly here is known to fit in it.
+			next, err = r.read()
+			hasNext = err == nil
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("reading %s: %w", in.Name(), err)
+		}
+		stats.Elements += int64(len(chunk))
+		atEOF := err == io.EOF
+
+		o.sortFunc(chunk)
+		if len(chunk) > 0 {
+			stats.Runs++
+		}
+		if atEOF && len(runs) == 0 {
+			return nil, writeIntFile(outputPath, o.format, externalMinBuffer, chunk)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("run-%d", len(runs)))
+		if err := writeIntFile(path, BinaryFormat, externalMinBuffer, chunk); err != nil {
+			return nil, err
+		}
+		runs = append(runs, path)
+		if atEOF {
+			return runs, nil
+		}
+	}
+}
+
+// mergeRunFiles merges the sorted BinaryFormat files runs into a new file at
+// path, splitting memoryBudget between the files' buffers.
+func mergeRunFiles(runs []string, path string, format ExternalFormat, memoryBudget int) (err error) {
+	bufSize := max(externalMinBuffer, memoryBudget/(len(runs)+1))
+	readers := make([]*intReader, len(runs))
+	for i, run := range runs {
+		f, err := os.Open(run)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers[i] = newIntReader(f, BinaryFormat, bufSize)
+	}
+
+	// Each Item holds the next integer of run Item.value as its priority.
+	pq := &PriorityQueue{}
+	for i, r := range readers {
+		num, err := r.read()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("reading %s: %w", runs[i], err)
+		}
+		pq.Push(&Item{value: i, priority: num})
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	w := newIntWriter(out, format, bufSize)
+	for len(*pq) > 0 {
+		item := pq.Pop()
+		w.write(item.priority)
+		num, err := readers[item.value].read()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("reading %s: %w", runs[item.value], err)
+		}
+		item.priority = num
+		pq.Push(item)
+	}
+	return w.flush()
+}
+
+// writeIntFile writes nums to a new file at path.
+func writeIntFile(path string, format ExternalFormat, bufSize int, nums []int) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	w := newIntWriter(f, format, bufSize)
+	for _, num := range nums {
+		w.write(num)
+	}
+	return w.flush()
+}
+
+// intReader reads integers in either ExternalFormat.
+type intReader struct {
+	r      *bufio.Reader
+	format ExternalFormat
+	line   int // lines read, for error messages
+	buf    [externalElementSize]byte
+}
+
+func newIntReader(r io.Reader, format ExternalFormat, bufSize int) *intReader {
+	return &intReader{r: bufio.NewReaderSize(r, bufSize), format: format}
+}
+
+// read returns the next integer, or io.EOF once there are none left.
+func (ir *intReader) read() (int, error) {
+	if ir.format == BinaryFormat {
+		if _, err := io.ReadFull(ir.r, ir.buf[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return 0, fmt.Errorf("truncated record: file size is not a multiple of %d bytes", externalElementSize)
+			}
+			return 0, err
+		}
+		return int(int64(binary.LittleEndian.Uint64(ir.buf[:]))), nil
+	}
+
+	for {
+		line, err := ir.r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return 0, fmt.Errorf("line %d is too long", ir.line+1)
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if len(line) == 0 && err == io.EOF {
+			return 0, io.EOF
+		}
+		ir.line++
+		text := bytes.TrimSpace(line)
+		if len(text) == 0 {
+			continue
+		}
+		num, parseErr := strconv.Atoi(string(text))
+		if parseErr != nil {
+			return 0, fmt.Errorf("line %d: %w", ir.line, parseErr)
+		}
+		return num, nil
+	}
+}
+
+// intWriter writes integers in either ExternalFormat. Write errors are held
+// by the bufio.Writer and reported by flush.
+type intWriter struct {
+	w      *bufio.Writer
+	format ExternalFormat
+	buf    []byte
+}
+
+func newIntWriter(w io.Writer, format ExternalFormat, bufSize int) *intWriter {
+	return &intWriter{w: bufio.NewWriterSize(w, bufSize), format: format, buf: make([]byte, 0, 24)}
+}
+
+func (iw *intWriter) write(num int) {
+	if iw.format == BinaryFormat {
+		iw.buf = binary.LittleEndian.AppendUint64(iw.buf[:0], uint64(num))
+	} else {
+		iw.buf = append(strconv.AppendInt(iw.buf[:0], int64(num), 10), '\n')
+	}
+	iw.w.Write(iw.buf)
+}
+
+func (iw *intWriter) flush() error {
+	return iw.w.Flush()
+}
+
+// TestExternalSort sorts files of random integers in both formats with a
+// memory budget small enough to force several merge passes, and checks the
+// output against slices.Sort.
+func TestExternalSort() {
+	dir, err := os.MkdirTemp("", "external-sort-test-*")
+	if err != nil {
+		fmt.Println("ExternalSort:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	rng := rand.New(rand.NewSource(1))
+	nums := make([]int, 200000)
+	for i := range nums {
+		nums[i] = rng.Intn(2000000) - 1000000
+	}
+	want := slices.Clone(nums)
+	slices.Sort(want)
+
+	for _, format := range []ExternalFormat{TextFormat, BinaryFormat} {
+		input := filepath.Join(dir, "input-"+format.String())
+		output := filepath.Join(dir, "output-"+format.String())
+		if err := writeIntFile(input, format, externalMinBuffer, nums); err != nil {
+			fmt.Println("ExternalSort:", err)
+			return
+		}
+		stats, err := ExternalSort(input, output, WithExternalFormat(format), WithMemoryBudget(32<<10))
+		if err != nil {
+			fmt.Printf("ExternalSort (%s): FAIL: %v\n", format, err)
+			continue
+		}
+
+		f, err := os.Open(output)
+		if err != nil {
+			fmt.Println("ExternalSort:", err)
+			return
+		}
+		var got []int
+		r := newIntReader(f, format, externalMinBuffer)
+		for num, err := r.read(); err == nil; num, err = r.read() {
+			got = append(got, num)
+		}
+		f.Close()
+
+		result := "ok"
+		if !slices.Equal(got, want) {
+			result = fmt.Sprintf("FAIL: output of %d integers does not match slices.Sort", len(got))
+		}
+		fmt.Printf("ExternalSort (%s): %s | %d elements, %d runs, %d merge passes\n",
+			format, result, stats.Elements, stats.Runs, stats.MergePasses)
+	}
+}
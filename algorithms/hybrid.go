@@ -0,0 +1,347 @@
+package algorithms
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// insertionSortThreshold is the partition size below which the hybrid sorts
+// switch to InsertionSort, whose low overhead beats quicksort on short slices.
+const insertionSortThreshold = 12
+
+// IntroSort
+// Introspective sort is QuickSort with two safety nets:
+//   - partitions of insertionSortThreshold elements or fewer are finished with InsertionSort
+//   - once the recursion is 2 * log2(N) levels deep, quicksort is making bad pivot choices,
+//     so the partition is finished with HeapSort instead
+//
+// The depth limit caps the worst case at O(N log N), while typical inputs keep
+// quicksort's speed. Pivots are the median of the first, middle and last elements,
+// and the larger partition is handled by a loop rather than recursion, which
+// keeps the stack O(log N) deep.
+func IntroSort(arr []int) {
+	IntroSortFunc(arr, cmp.Compare[int])
+}
+
+// IntroSortFunc sorts arr with IntroSort in the order defined by cmp.
+func IntroSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	introSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func introSort[T any](s *sorter[T], arr []T) {
+	introSortLoop(s, arr, 2*bits.Len(uint(len(arr))))
+}
+
+func introSortLoop[T any](s *sorter[T], arr []T, depthLimit int) {
+	s.enter()
+	defer s.leave()
+	for len(arr) > insertionSortThreshold {
+		if depthLimit == 0 {
+			heapSort(s, arr)
+			return
+		}
+		depthLimit--
+
+		medianOfThreeToMiddle(s, arr)
+		pivotIndex := partition(s, arr, 0, len(arr)-1)
+
+		// Recurse into the smaller partition and loop on the larger one.
+		if pivotIndex+1 < len(arr)-(pivotIndex+1) {
+			introSortLoop(s, arr[:pivotIndex+1], depthLimit)
+			arr = arr[pivotIndex+1:]
+		} else {
+			introSortLoop(s, arr[pivotIndex+1:], depthLimit)
+			arr = arr[:pivotIndex+1]
+		}
+	}
+	insertionSort(s, arr)
+}
+
+// medianOfThreeToMiddle orders the first, middle and last elements of arr, so
+// that their median is in the middle, where partition takes its pivot.
+func medianOfThreeToMiddle[T any](s *sorter[T], arr []T) {
+	high := len(arr) - 1
+	mid := high / 2
+	if s.less(arr[mid], arr[0]) {
+		s.swap(arr, mid, 0)
+	}
+	if s.less(arr[high], arr[mid]) {
+		s.swap(arr, high, mid)
+		if s.less(arr[mid], arr[0]) {
+			s.swap(arr, mid, 0)
+		}
+	}
+}
+
+// PdqSort
+// Pattern-defeating quicksort (Orson Peters) is the introsort variant behind the
+// standard library's sort.Sort and slices.Sort. On top of introsort it:
+//   - samples the pivot candidates (a ninther of nine elements on large partitions) and,
+//     if they were already in order, tries to finish an almost sorted partition with a
+//     bounded insertion sort; candidates in reverse order reverse the partition first
+//   - notices when a partition was badly unbalanced and shuffles a few elements to
+//     break up patterns that defeat the pivot choice, giving HeapSort a budget of
+//     log2(N) such failures
+//   - when the pivot equals the element just before the partition, gathers every element
+//     equal to it in one pass, so inputs with few distinct values take O(N) per value
+//
+// Sorted, reversed and few-unique inputs run in O(N) time, and the worst case
+// is O(N log N).
+func PdqSort(arr []int) {
+	PdqSortFunc(arr, cmp.Compare[int])
+}
+
+// PdqSortFunc sorts arr with PdqSort in the order defined by cmp.
+func PdqSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	pdqSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func pdqSort[T any](s *sorter[T], arr []T) {
+	pdqSortLoop(s, arr, 0, len(arr), bits.Len(uint(len(arr))))
+}
+
+type sortedHint int
+
+const (
+	unknownHint sortedHint = iota
+	increasingHint
+	decreasingHint
+)
+
+// pdqSortLoop sorts arr[a:b]. limit is the number of unbalanced partitions
+// allowed before falling back to HeapSort.
+func pdqSortLoop[T any](s *sorter[T], arr []T, a, b, limit int) {
+	s.enter()
+	defer s.leave()
+	wasBalanced, wasPartitioned := true, true
+	for {
+		length := b - a
+		if length <= insertionSortThreshold {
+			insertionSort(s, arr[a:b])
+			return
+		}
+		// Fall back to HeapSort if too many bad pivots were chosen.
+		if limit == 0 {
+			heapSort(s, arr[a:b])
+			return
+		}
+		// If the last partitioning was unbalanced, shuffle the pattern that caused it.
+		if !wasBalanced {
+			pdqBreakPatterns(s, arr, a, b)
+			limit--
+		}
+
+		pivot, hint := pdqChoosePivot(s, arr, a, b)
+		if hint == decreasingHint {
+			pdqReverseRange(s, arr, a, b)
+			// The pivot moved with the reversal.
+			pivot = (b - 1) - (pivot - a)
+			hint = increasingHint
+		}
+
+		// The partition is likely already sorted.
+		if wasBalanced && wasPartitioned && hint == increasingHint {
+			if pdqPartialInsertionSort(s, arr, a, b) {
+				return
+			}
+		}
+
+		// The element before the partition is <= every element in it. If it is not
+		// smaller than the pivot, the pivot is the partition's minimum and probably
+		// repeated, so split off every element equal to it.
+		if a > 0 && !s.less(arr[a-1], arr[pivot]) {
+			a = pdqPartitionEqual(s, arr, a, b, pivot)
+			continue
+		}
+
+		mid, alreadyPartitioned := pdqPartition(s, arr, a, b, pivot)
+		wasPartitioned = alreadyPartitioned
+
+		leftLen, rightLen := mid-a, b-mid
+		balanceThreshold := length / 8
+		if leftLen < rightLen {
+			wasBalanced = leftLen >= balanceThreshold
+			pdqSortLoop(s, arr, a, mid, limit)
+			a = mid + 1
+		} else {
+			wasBalanced = rightLen >= balanceThreshold
+			pdqSortLoop(s, arr, mid+1, b, limit)
+			b = mid
+		}
+	}
+}
+
+// pdqPartition partitions arr[a:b] around arr[pivot], returning the pivot's
+// final index and whether the partition needed no swaps.
+func pdqPartition[T any](s *sorter[T], arr []T, a, b, pivot int) (int, bool) {
+	s.swap(arr, a, pivot)
+	i, j := a+1, b-1 // i and j are inclusive of the elements remaining to be partitioned
+	for i <= j && s.less(arr[i], arr[a]) {
+		i++
+	}
+	for i <= j && !s.less(arr[j], arr[a]) {
+		j--
+	}
+	if i > j {
+		s.swap(arr, j, a)
+		return j, true
+	}
+	s.swap(arr, i, j)
+	i++
+	j--
+	for {
+		for i <= j && s.less(arr[i], arr[a]) {
+			i++
+		}
+		for i <= j && !s.less(arr[j], arr[a]) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		s.swap(arr, i, j)
+		i++
+		j--
+	}
+	s.swap(arr, j, a)
+	return j, false
+}
+
+// pdqPartitionEqual moves the elements of arr[a:b] equal to arr[pivot] to the
+// front, given that none are smaller, and returns the index just past them.
+func pdqPartitionEqual[T any](s *sorter[T], arr []T, a, b, pivot int) int {
+	s.swap(arr, a, pivot)
+	i, j := a+1, b-1 // i and j are inclusive of the elements remaining to be partitioned
+	for {
+		for i <= j && !s.less(arr[a], arr[i]) {
+			i++
+		}
+		for i <= j && s.less(arr[a], arr[j]) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		s.swap(arr, i, j)
+		i++
+		j--
+	}
+	return i
+}
+
+// pdqPartialInsertionSort sorts arr[a:b] if doing so takes only a few shifts,
+// reporting whether it did.
+func pdqPartialInsertionSort[T any](s *sorter[T], arr []T, a, b int) bool {
+	const (
+		maxSteps         = 5  // maximum number of adjacent out-of-order pairs that will get shifted
+		shortestShifting = 50 // don't shift any elements on short slices
+	)
+	i := a + 1
+	for step := 0; step < maxSteps; step++ {
+		for i < b && !s.less(arr[i], arr[i-1]) {
+			i++
+		}
+		if i == b {
+			return true
+		}
+		if b-a < shortestShifting {
+			return false
+		}
+		s.swap(arr, i, i-1)
+
+		// Shift the smaller one to the left.
+		if i-a >= 2 {
+			for j := i - 1; j >= 1; j-- {
+				if !s.less(arr[j], arr[j-1]) {
+					break
+				}
+				s.swap(arr, j, j-1)
+			}
+		}
+		// Shift the greater one to the right.
+		if b-i >= 2 {
+			for j := i + 1; j < b; j++ {
+				if !s.less(arr[j], arr[j-1]) {
+					break
+				}
+				s.swap(arr, j, j-1)
+			}
+		}
+	}
+	return false
+}
+
+// pdqBreakPatterns swaps three elements around the middle of arr[a:b] with
+// pseudo-randomly chosen ones.
+func pdqBreakPatterns[T any](s *sorter[T], arr []T, a, b int) {
+	length := b - a
+	if length < 8 {
+		return
+	}
+	random := uint64(length) // xorshift state, seeded by length so runs are reproducible
+	modulus := uint(1) << bits.Len(uint(length))
+	idx := a + (length/4)*2 - 1
+	for i := 0; i < 3; i++ {
+		random ^= random << 13
+		random ^= random >> 7
+		random ^= random << 17
+		other := int(uint(random) & (modulus - 1))
+		if other >= length {
+			other -= length
+		}
+		s.swap(arr, idx-1+i, a+other)
+	}
+}
+
+// pdqChoosePivot returns the index of a pivot for arr[a:b]: the median of three
+// candidates, each of which is itself the median of three adjacent elements on
+// larger partitions. The hint reports whether the candidates were in increasing
+// or decreasing order.
+func pdqChoosePivot[T any](s *sorter[T], arr []T, a, b int) (int, sortedHint) {
+	const (
+		shortestNinther = 50
+		maxSwaps        = 4 * 3
+	)
+	l := b - a
+	swaps := 0
+	i, j, k := a+l/4*1, a+l/4*2, a+l/4*3
+	if l >= 8 {
+		if l >= shortestNinther {
+			// Tukey's ninther: the median of three medians of three.
+			i = pdqMedian(s, arr, i-1, i, i+1, &swaps)
+			j = pdqMedian(s, arr, j-1, j, j+1, &swaps)
+			k = pdqMedian(s, arr, k-1, k, k+1, &swaps)
+		}
+		j = pdqMedian(s, arr, i, j, k, &swaps)
+	}
+	switch swaps {
+	case 0:
+		return j, increasingHint
+	case maxSwaps:
+		return j, decreasingHint
+	default:
+		return j, unknownHint
+	}
+}
+
+// pdqMedian returns whichever of a, b and c indexes the median of the three
+// elements, counting in swaps how many pairs were out of order.
+func pdqMedian[T any](s *sorter[T], arr []T, a, b, c int, swaps *int) int {
+	order := func(x, y int) (int, int) {
+		if s.less(arr[y], arr[x]) {
+			*swaps++
+			return y, x
+		}
+		return x, y
+	}
+	a, b = order(a, b)
+	b, c = order(b, c)
+	_, b = order(a, b)
+	return b
+}
+
+func pdqReverseRange[T any](s *sorter[T], arr []T, a, b int) {
+	for i, j := a, b-1; i < j; i, j = i+1, j-1 {
+		s.swap(arr, i, j)
+	}
+}
@@ -0,0 +1,367 @@
+package algorithms
+
+import (
+	"cmp"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// defaultParallelThreshold is the partition size at or below which the
+	// parallel sorts stop spawning goroutines and sort serially. Smaller
+	// partitions finish faster than a goroutine can be scheduled to help.
+	defaultParallelThreshold = 1 << 13
+	// sampleSortBucketsPerWorker is how many buckets SampleSort makes per
+	// goroutine, so that uneven buckets still spread evenly over the goroutines.
+	sampleSortBucketsPerWorker = 4
+	// sampleSortOversampling is how many sampled elements SampleSort takes per
+	// bucket; more samples give more evenly sized buckets.
+	sampleSortOversampling = 16
+)
+
+// ParallelOption configures ParallelMergeSort, ParallelQuickSort and SampleSort.
+type ParallelOption func(*parallelOptions)
+
+type parallelOptions struct {
+	threshold      int // partitions of this many elements or fewer are sorted serially
+	maxParallelism int // goroutines sorting at once, the caller's included; <= 0 means GOMAXPROCS
+}
+
+func newParallelOptions(opts []ParallelOption) parallelOptions {
+	o := parallelOptions{threshold: defaultParallelThreshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.threshold = max(o.threshold, 1)
+	if o.maxParallelism <= 0 {
+		o.maxParallelism = runtime.GOMAXPROCS(0)
+	}
+	return o
+}
+
+// WithParallelThreshold sets the partition size at or below which a parallel
+// sort stops spawning goroutines and finishes the partition serially. The
+// default is 8192.
+func WithParallelThreshold(n int) ParallelOption {
+	return func(o *parallelOptions) {
+		o.threshold = n
+	}
+}
+
+// WithMaxParallelism caps the number of goroutines sorting at once, including
+// the calling one. 1 sorts serially, and n <= 0, the default, means
+// runtime.GOMAXPROCS(0) at the time of the sort.
+func WithMaxParallelism(n int) ParallelOption {
+	return func(o *parallelOptions) {
+		o.maxParallelism = n
+	}
+}
+
+// workers hands out permission to start goroutines, so that no more than
+// maxParallelism goroutines sort at once however deep the recursion goes.
+type workers struct {
+	threshold int
+	tokens    chan struct{} // one per goroutine allowed besides the caller's
+}
+
+func newWorkers(o parallelOptions) *workers {
+	return &workers{threshold: o.threshold, tokens: make(chan struct{}, o.maxParallelism-1)}
+}
+
+// both runs first and second, first on a new goroutine if one is allowed, and
+// returns once both have finished. If the sort is canceled, it panics with
+// sortCanceled once both have stopped.
+func both[T any](w *workers, s *sorter[T], first, second func(s *sorter[T])) {
+	select {
+	case w.tokens <- struct{}{}:
+		child := s.fork()
+		var wg sync.WaitGroup
+		var canceled atomic.Bool
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-w.tokens }()
+			defer recoverCanceled(&canceled)
+			first(child)
+		}()
+		func() {
+			defer wg.Wait() // even if second is canceled
+			second(s)
+		}()
+		if canceled.Load() {
+			panic(sortCanceled{})
+		}
+		s.join(child)
+	default:
+		first(s)
+		second(s)
+	}
+}
+
+// each runs task for every i in [0, n) on up to maxParallelism goroutines,
+// each taking the next unclaimed i as it finishes the last. If the sort is
+// canceled, it panics with sortCanceled once every goroutine has stopped.
+func each[T any](w *workers, s *sorter[T], n int, task func(s *sorter[T], i int)) {
+	g := min(n, cap(w.tokens)+1)
+	if g <= 1 {
+		for i := 0; i < n; i++ {
+			task(s, i)
+		}
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	var canceled atomic.Bool
+	children := make([]*sorter[T], g)
+	for j := range children {
+		children[j] = s.fork()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer recoverCanceled(&canceled)
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				task(children[j], i)
+			}
+		}()
+	}
+	wg.Wait()
+	if canceled.Load() {
+		panic(sortCanceled{})
+	}
+	for _, child := range children {
+		s.join(child)
+	}
+}
+
+// ParallelMergeSort
+// MergeSort with the two halves of each split sorted concurrently:
+//   - halves larger than the threshold are split again, one half on a new goroutine while
+//     no more than maxParallelism are running and the other on the current one
+//   - halves at or below the threshold are sorted with the serial MergeSort
+//   - each pair of sorted halves is then merged through one shared buffer of N elements,
+//     skipping the merge if the halves are already in order
+//
+// The work is O(N log N) as for MergeSort, but the final merges run on one
+// goroutine each, which bounds the speedup: the last merge alone takes O(N).
+// The sort is stable.
+func ParallelMergeSort(arr []int, opts ...ParallelOption) {
+	ParallelMergeSortFunc(arr, cmp.Compare[int], opts...)
+}
+
+// ParallelMergeSortFunc sorts arr with ParallelMergeSort in the order defined
+// by cmp. It is stable.
+func ParallelMergeSortFunc[T any](arr []T, cmp func(a, b T) int, opts ...ParallelOption) {
+	parallelMergeSort(&sorter[T]{cmp: cmp}, arr, newParallelOptions(opts))
+}
+
+func parallelMergeSort[T any](s *sorter[T], arr []T, o parallelOptions) {
+	if len(arr) <= o.threshold {
+		mergeSort(s, arr)
+		return
+	}
+	parallelMergeSortSplit(newWorkers(o), s, arr, makeBuffer(s, len(arr)))
+}
+
+// parallelMergeSortSplit sorts arr using buf, which is as long as arr and
+// shared with no other goroutine, to merge.
+func parallelMergeSortSplit[T any](w *workers, s *sorter[T], arr, buf []T) {
+	if len(arr) <= w.threshold {
+		mergeSort(s, arr)
+		return
+	}
+	s.enter()
+	defer s.leave()
+	mid := len(arr) / 2
+	both(w, s,
+		func(s *sorter[T]) { parallelMergeSortSplit(w, s, arr[:mid], buf[:mid]) },
+		func(s *sorter[T]) { parallelMergeSortSplit(w, s, arr[mid:], buf[mid:]) },
+	)
+	if s.less(arr[mid], arr[mid-1]) {
+		mergeRuns(s, arr, mid, buf)
+	}
+}
+
+// ParallelQuickSort
+// QuickSort with the two partitions of each split sorted concurrently:
+//   - the pivot is the median of the first, middle and last elements
+//   - partitions larger than the threshold are split again, one on a new goroutine while
+//     no more than maxParallelism are running and the other on the current one
+//   - partitions at or below the threshold are sorted with the serial IntroSort
+//   - as in IntroSort, a split 2 * log2(N) levels deep falls back to HeapSort
+//
+// Unlike merge sort, the work that cannot be spread out comes first: the
+// partitioning of the whole input takes O(N) on one goroutine before the
+// second goroutine has anything to do.
+func ParallelQuickSort(arr []int, opts ...ParallelOption) {
+	ParallelQuickSortFunc(arr, cmp.Compare[int], opts...)
+}
+
+// ParallelQuickSortFunc sorts arr with ParallelQuickSort in the order defined
+// by cmp.
+func ParallelQuickSortFunc[T any](arr []T, cmp func(a, b T) int, opts ...ParallelOption) {
+	parallelQuickSort(&sorter[T]{cmp: cmp}, arr, newParallelOptions(opts))
+}
+
+func parallelQuickSort[T any](s *sorter[T], arr []T, o parallelOptions) {
+	parallelQuickSortSplit(newWorkers(o), s, arr, 2*bits.Len(uint(len(arr))))
+}
+
+func parallelQuickSortSplit[T any](w *workers, s *sorter[T], arr []T, depthLimit int) {
+	if len(arr) <= w.threshold {
+		introSortLoop(s, arr, depthLimit)
+		return
+	}
+	s.enter()
+	defer s.leave()
+	if depthLimit == 0 {
+		heapSort(s, arr)
+		return
+	}
+	medianOfThreeToMiddle(s, arr)
+	pivotIndex := partition(s, arr, 0, len(arr)-1)
+	both(w, s,
+		func(s *sorter[T]) { parallelQuickSortSplit(w, s, arr[:pivotIndex+1], depthLimit-1) },
+		func(s *sorter[T]) { parallelQuickSortSplit(w, s, arr[pivotIndex+1:], depthLimit-1) },
+	)
+}
+
+// SampleSort
+// Sample sort splits the input into buckets of about equal size in one pass,
+// so that every goroutine has work from the start:
+//   - a random sample of the input is sorted, and evenly spaced elements of it become the
+//     splitters between sampleSortBucketsPerWorker buckets per goroutine
+//   - the input is cut into chunks, and concurrently each chunk's elements are assigned to
+//     buckets by binary search among the splitters
+//   - prefix sums over the per-chunk bucket sizes give each chunk a disjoint region of
+//     every bucket in a buffer, so the chunks are copied into it concurrently too
+//   - the buckets are sorted concurrently with PdqSort and copied back
+//
+// That is O(N log N) work, and with P goroutines O((N/P) log N) time, as long
+// as the sample predicts the buckets' sizes well. Inputs at or below the
+// threshold, or too small to sample, are sorted with PdqSort directly. The
+// sample is drawn with a seed derived from the input's length, so runs are
+// reproducible.
+func SampleSort(arr []int, opts ...ParallelOption) {
+	SampleSortFunc(arr, cmp.Compare[int], opts...)
+}
+
+// SampleSortFunc sorts arr with SampleSort in the order defined by cmp.
+func SampleSortFunc[T any](arr []T, cmp func(a, b T) int, opts ...ParallelOption) {
+	sampleSort(&sorter[T]{cmp: cmp}, arr, newParallelOptions(opts))
+}
+
+func sampleSort[T any](s *sorter[T], arr []T, o parallelOptions) {
+	n := len(arr)
+	k := max(2, o.maxParallelism*sampleSortBucketsPerWorker)
+	if o.maxParallelism == 1 || n <= o.threshold || n < k*sampleSortOversampling {
+		pdqSort(s, arr)
+		return
+	}
+	w := newWorkers(o)
+
+	// Take every sampleSortOversampling-th element of the sorted sample as a splitter.
+	rng := rand.New(rand.NewSource(int64(n)))
+	sample := makeBuffer(s, k*sampleSortOversampling)
+	for i := range sample {
+		sample[i] = arr[rng.Intn(n)]
+	}
+	s.moved(len(sample))
+	pdqSort(s, sample)
+	splitters := makeBuffer(s, k-1)
+	for i := range splitters {
+		splitters[i] = sample[(i+1)*sampleSortOversampling]
+	}
+	s.moved(len(splitters))
+
+	// bucketOf(x) is the number of splitters <= x, so equal elements share a bucket.
+	bucketOf := func(s *sorter[T], x T) int {
+		lo, hi := 0, len(splitters)
+		for lo < hi {
+			mid := int(uint(lo+hi) >> 1)
+			if s.less(x, splitters[mid]) {
+				hi = mid
+			} else {
+				lo = mid + 1
+			}
+		}
+		return lo
+	}
+
+	chunks := k
+	chunk := func(c int) (int, int) { return c * n / chunks, (c + 1) * n / chunks }
+	buckets := make([]int, n)
+	s.allocated(n)
+	counts := make([][]int, chunks) // counts[c][b] is the size of chunk c's share of bucket b
+	each(w, s, chunks, func(s *sorter[T], c int) {
+		counts[c] = make([]int, k)
+		s.allocated(k)
+		lo, hi := chunk(c)
+		for i := lo; i < hi; i++ {
+			b := bucketOf(s, arr[i])
+			buckets[i] = b
+			counts[c][b]++
+		}
+	})
+
+	// Lay out the buckets in order, each made of its chunks' shares in order,
+	// and turn counts[c][b] into the index at which chunk c's share starts.
+	starts := make([]int, k+1)
+	s.allocated(k + 1)
+	pos := 0
+	for b := 0; b < k; b++ {
+		starts[b] = pos
+		for c := 0; c < chunks; c++ {
+			pos, counts[c][b] = pos+counts[c][b], pos
+		}
+	}
+	starts[k] = n
+
+	buf := makeBuffer(s, n)
+	each(w, s, chunks, func(s *sorter[T], c int) {
+		lo, hi := chunk(c)
+		for i := lo; i < hi; i++ {
+			b := buckets[i]
+			buf[counts[c][b]] = arr[i]
+			counts[c][b]++
+		}
+		s.moved(hi - lo)
+	})
+	each(w, s, k, func(s *sorter[T], b int) {
+		bucket := buf[starts[b]:starts[b+1]]
+		pdqSort(s, bucket)
+		s.moved(copy(arr[starts[b]:], bucket))
+	})
+}
+
+// parallelAlgorithms returns the parallel sorts as SortingAlgorithms
+// configured with opts.
+func parallelAlgorithms(opts ...ParallelOption) []SortingAlgorithm {
+	withOptions := func(sort func(s *sorter[int], arr []int, o parallelOptions)) func(s *sorter[int], arr []int) {
+		return func(s *sorter[int], arr []int) {
+			sort(s, arr, newParallelOptions(opts))
+		}
+	}
+	return []SortingAlgorithm{
+		newSortingAlgorithm("ParallelMergeSort", withOptions(parallelMergeSort[int]), true, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+			"Span":       "O(n), from the final merge",
+		}),
+		newSortingAlgorithm("ParallelQuickSort", withOptions(parallelQuickSort[int]), false, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+			"Span":       "O(n), from the first partition",
+		}),
+		newSortingAlgorithm("SampleSort", withOptions(sampleSort[int]), false, map[string]string{
+			"Best Case":  "Ω(n)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+			"Span":       "O((n/p)logn) for p goroutines",
+		}),
+	}
+}
@@ -0,0 +1,198 @@
+package algorithms
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// referenceAlgorithm names the standard library's pdqsort, which every
+// benchmark runs as a fixed reference point. It cannot be instrumented.
+const referenceAlgorithm = "slices.Sort"
+
+// SortingAlgorithm describes an integer sort that BenchmarkSortAlgorithms can run.
+type SortingAlgorithm struct {
+	Name     string
+	SortFunc func(arr []int)
+	// CountingSortFunc, if set, sorts arr exactly like SortFunc while tallying
+	// the operations it performs into counts. It runs slower than SortFunc, so
+	// time SortFunc and count with CountingSortFunc.
+	CountingSortFunc func(arr []int, counts *OpCounts)
+	// CompareSortFunc, set for comparison sorts, sorts arr in the order defined
+	// by cmp rather than numerically, e.g. to sort indexes by a key.
+	CompareSortFunc func(arr []int, cmp func(a, b int) int)
+	// Stable reports whether elements that compare equal keep their original order.
+	Stable         bool
+	TimeComplexity map[string]string
+	// sort is the implementation behind SortFunc and CountingSortFunc, or nil
+	// if the algorithm is not written against a sorter.
+	sort func(s *sorter[int], arr []int)
+}
+
+// Count sorts arr with CountingSortFunc and returns its operation counts. It
+// reports false if the algorithm is not instrumented.
+func (a SortingAlgorithm) Count(arr []int) (OpCounts, bool) {
+	var counts OpCounts
+	if a.CountingSortFunc == nil {
+		return counts, false
+	}
+	a.CountingSortFunc(arr, &counts)
+	return counts, true
+}
+
+// SortContext sorts arr like SortFunc, but gives up once ctx is done and
+// returns its error, leaving arr partly sorted. Algorithms that are not written
+// against a sorter only check ctx before they start.
+func (a SortingAlgorithm) SortContext(ctx context.Context, arr []int) error {
+	sort, release := a.sortContextFunc(ctx)
+	defer release()
+	return sort(arr)
+}
+
+// sortContextFunc returns SortContext with ctx set up ahead, to sort any
+// number of inputs, and a function to call once done with it.
+func (a SortingAlgorithm) sortContextFunc(ctx context.Context) (sort func(arr []int) error, release func()) {
+	if ctx.Done() == nil {
+		// ctx can never be canceled, so skip the checks.
+		return func(arr []int) error {
+			a.SortFunc(arr)
+			return nil
+		}, func() {}
+	}
+	if a.sort == nil {
+		return func(arr []int) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			a.SortFunc(arr)
+			return nil
+		}, func() {}
+	}
+	return cancelableSort(ctx, &sorter[int]{cmp: cmp.Compare[int]}, a.sort)
+}
+
+// countContext is Count, giving up like SortContext once ctx is done.
+func (a SortingAlgorithm) countContext(ctx context.Context, arr []int) (OpCounts, bool, error) {
+	var counts OpCounts
+	if a.sort == nil {
+		return counts, false, ctx.Err()
+	}
+	count, release := cancelableSort(ctx, &sorter[int]{cmp: cmp.Compare[int], counts: &counts}, a.sort)
+	defer release()
+	err := count(arr)
+	return counts, true, err
+}
+
+// newSortingAlgorithm builds a SortingAlgorithm for a comparison sort whose
+// plain, counting and comparator forms share one implementation written
+// against a sorter.
+func newSortingAlgorithm(name string, sort func(s *sorter[int], arr []int), stable bool, timeComplexity map[string]string) SortingAlgorithm {
+	algo := newIntSortingAlgorithm(name, sort, timeComplexity)
+	algo.CompareSortFunc = func(arr []int, cmp func(a, b int) int) {
+		sort(&sorter[int]{cmp: cmp}, arr)
+	}
+	algo.Stable = stable
+	return algo
+}
+
+// newIntSortingAlgorithm builds a SortingAlgorithm for a sort that orders
+// integers by their value, such as a radix sort, and so cannot take a comparator.
+func newIntSortingAlgorithm(name string, sort func(s *sorter[int], arr []int), timeComplexity map[string]string) SortingAlgorithm {
+	return SortingAlgorithm{
+		Name: name,
+		SortFunc: func(arr []int) {
+			sort(&sorter[int]{cmp: cmp.Compare[int]}, arr)
+		},
+		CountingSortFunc: func(arr []int, counts *OpCounts) {
+			sort(&sorter[int]{cmp: cmp.Compare[int], counts: counts}, arr)
+		},
+		TimeComplexity: timeComplexity,
+		sort:           sort,
+	}
+}
+
+// SortingAlgorithms returns every registered sorting algorithm, in the order
+// the benchmark runs them.
+func SortingAlgorithms() []SortingAlgorithm {
+	algos := []SortingAlgorithm{
+		newSortingAlgorithm("QuickSort", quickSort[int], false, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(n²)",
+		}),
+		newSortingAlgorithm("HeapSort", heapSort[int], false, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		}),
+		newIntSortingAlgorithm("RadixSort", radixSort, map[string]string{
+			"Best Case":  "Ω(nk)",
+			"Avg Case":   "θ(nk)",
+			"Worst Case": "O(nk)",
+			"k":          "num of digits in max - min",
+		}),
+		newIntSortingAlgorithm("LSDRadixSort", lsdRadixSort, map[string]string{
+			"Best Case":  "Ω(nk)",
+			"Avg Case":   "θ(nk)",
+			"Worst Case": "O(nk)",
+			"k":          "num of bytes in max - min",
+		}),
+		newIntSortingAlgorithm("CountingSort", countingSort, map[string]string{
+			"Best Case":  "Ω(n+k)",
+			"Avg Case":   "θ(n+k)",
+			"Worst Case": "O(n+k)",
+			"k":          "max - min, above which it falls back to LSDRadixSort",
+		}),
+		newSortingAlgorithm("MergeSort", mergeSort[int], true, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		}),
+		newSortingAlgorithm("ShellSort", shellSort[int], false, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlog²n) <= between => θ(n²)",
+			"Worst Case": "O(n²)",
+		}),
+		newSortingAlgorithm("InsertionSort", insertionSort[int], true, map[string]string{
+			"Best Case":  "Ω(n)",
+			"Avg Case":   "θ(n²)",
+			"Worst Case": "O(n²)",
+		}),
+		newSortingAlgorithm("SelectionSort", selectionSort[int], false, map[string]string{
+			"Best Case":  "Ω(n²)",
+			"Avg Case":   "θ(n²)",
+			"Worst Case": "O(n²)",
+		}),
+		newSortingAlgorithm("IntroSort", introSort[int], false, map[string]string{
+			"Best Case":  "Ω(nlogn)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		}),
+		newSortingAlgorithm("PdqSort", pdqSort[int], false, map[string]string{
+			"Best Case":  "Ω(n)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		}),
+		newSortingAlgorithm("TimSort", timSort[int], true, map[string]string{
+			"Best Case":  "Ω(n)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		}),
+		newSortingAlgorithm("NaturalMergeSort", naturalMergeSort[int], true, map[string]string{
+			"Best Case":  "Ω(n)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		}),
+	}
+	algos = append(algos, parallelAlgorithms()...)
+	return append(algos, SortingAlgorithm{
+		Name:            referenceAlgorithm,
+		SortFunc:        slices.Sort[[]int],
+		CompareSortFunc: slices.SortFunc[[]int],
+		TimeComplexity: map[string]string{
+			"Best Case":  "Ω(n)",
+			"Avg Case":   "θ(nlogn)",
+			"Worst Case": "O(nlogn)",
+		},
+	})
+}
@@ -0,0 +1,137 @@
+package algorithms
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sort sorts s in ascending order. It uses MergeSort, so it is stable and
+// O(n log n) even in the worst case. Floating-point NaNs sort first, as with
+// cmp.Compare.
+func Sort[T cmp.Ordered](s []T) {
+	MergeSortFunc(s, cmp.Compare[T])
+}
+
+// SortFunc sorts s in the order defined by cmp, which returns a negative number
+// when a sorts before b, a positive number when a sorts after b and zero when
+// they are equal. Like Sort, it is stable.
+//
+// Descending, ByKey and ThenBy build comparators for common orders, e.g. to sort
+// people oldest first, then by name:
+//
+//	SortFunc(people, ThenBy(Descending(ByKey(func(p Person) int { return p.Age })),
+//		ByKey(func(p Person) string { return p.Name })))
+func SortFunc[T any](s []T, cmp func(a, b T) int) {
+	MergeSortFunc(s, cmp)
+}
+
+// Descending reverses the order defined by cmp.
+func Descending[T any](cmp func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp(b, a)
+	}
+}
+
+// ByKey returns a comparator that orders values by the key extracted from each.
+func ByKey[T any, K cmp.Ordered](key func(T) K) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ThenBy combines comparators into a multi-key order: values are ordered by the
+// first comparator, ties are broken by the second, and so on.
+func ThenBy[T any](cmps ...func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// SliceFunc adapts a slice and comparator to sort.Interface, for use with
+// sort.Sort, sort.Stable or any other code written against that interface.
+type SliceFunc[T any] struct {
+	Items []T
+	Cmp   func(a, b T) int
+}
+
+func (s SliceFunc[T]) Len() int           { return len(s.Items) }
+func (s SliceFunc[T]) Less(i, j int) bool { return s.Cmp(s.Items[i], s.Items[j]) < 0 }
+func (s SliceFunc[T]) Swap(i, j int)      { s.Items[i], s.Items[j] = s.Items[j], s.Items[i] }
+
+// SortInterface sorts data with one of this package's algorithms, given as its
+// comparator-based form, e.g. SortInterface(data, HeapSortFunc[int]).
+//
+// sort.Interface only exposes Less and Swap, so the algorithm sorts a slice of
+// data's indexes instead, comparing them with data.Less. data is then
+// rearranged into that order with at most Len()-1 calls to Swap. Stable
+// algorithms stay stable.
+func SortInterface(data sort.Interface, sortFunc func(arr []int, cmp func(a, b int) int)) {
+	perm := make([]int, data.Len())
+	for i := range perm {
+		perm[i] = i
+	}
+	sortFunc(perm, func(a, b int) int {
+		if data.Less(a, b) {
+			return -1
+		}
+		if data.Less(b, a) {
+			return 1
+		}
+		return 0
+	})
+
+	// perm[i] is the index of the element that belongs at i. Follow each cycle
+	// of the permutation, swapping its elements into place.
+	for i := range perm {
+		j := i
+		for perm[j] != i {
+			next := perm[j]
+			data.Swap(j, next)
+			perm[j] = j
+			j = next
+		}
+		perm[j] = j
+	}
+}
+
+// TestGenericSort demonstrates sorting strings, floats and structs with the
+// comparator-based API and the sort.Interface adapters.
+func TestGenericSort() {
+	words := []string{"pear", "Apple", "fig", "banana", "cherry"}
+	Sort(words)
+	fmt.Printf("strings: %v\n", words)
+
+	InsertionSortFunc(words, ThenBy(ByKey(func(w string) int { return len(w) }), ByKey(strings.ToLower)))
+	fmt.Printf("strings by length, then case-insensitively: %v\n", words)
+
+	floats := []float64{3.5, -1.25, 2, 0, -7.5}
+	HeapSortFunc(floats, Descending(cmp.Compare[float64]))
+	fmt.Printf("floats descending: %v\n", floats)
+
+	type person struct {
+		Name string
+		Age  int
+	}
+	people := []person{{"Ada", 36}, {"Linus", 28}, {"Grace", 36}, {"Ken", 28}, {"Barbara", 41}}
+	byAgeDescThenName := ThenBy(
+		Descending(ByKey(func(p person) int { return p.Age })),
+		ByKey(func(p person) string { return p.Name }),
+	)
+	SortFunc(people, byAgeDescThenName)
+	fmt.Printf("people by age descending, then name: %v\n", people)
+
+	// Sort the same people through sort.Interface, with our algorithm and with the standard library's.
+	byName := SliceFunc[person]{Items: people, Cmp: ByKey(func(p person) string { return p.Name })}
+	SortInterface(byName, SelectionSortFunc[int])
+	fmt.Printf("people by name (SelectionSort via sort.Interface): %v\n", people)
+	byName.Cmp = byAgeDescThenName
+	sort.Stable(byName)
+	fmt.Printf("people by age descending, then name (sort.Stable): %v\n", people)
+}
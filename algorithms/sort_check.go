@@ -0,0 +1,276 @@
+package algorithms
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+const (
+	// sortCheckSeed seeds the random inputs CheckSortingAlgorithm generates, so
+	// that a failure can be reproduced.
+	sortCheckSeed = 1
+	// sortCheckTrials is the number of random inputs checked per algorithm.
+	sortCheckTrials = 300
+	// sortCheckMaxLen bounds the length of the random inputs.
+	sortCheckMaxLen = 300
+)
+
+// sortEdgeCases are the inputs every algorithm is checked against before any
+// random ones.
+var sortEdgeCases = map[string][]int{
+	"empty":      {},
+	"single":     {42},
+	"pair":       {2, 1},
+	"all-equal":  {7, 7, 7, 7, 7, 7},
+	"duplicates": {5, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5},
+	"negatives":  {-3, 10, -1, 0, -100, 7, -3, 42, -7},
+	"extremes":   {0, math.MaxInt, math.MinInt, -1, 1, math.MinInt + 1, math.MaxInt - 1, math.MinInt},
+	"sorted":     {-5, -2, 0, 1, 1, 3, 8, 13, 21, 34},
+	"reversed":   {34, 21, 13, 8, 3, 1, 1, 0, -2, -5},
+}
+
+// CheckSortingAlgorithms runs CheckSortingAlgorithm against every registered
+// algorithm and reports every failure found.
+func CheckSortingAlgorithms() error {
+	var errs []error
+	for _, algo := range SortingAlgorithms() {
+		if err := CheckSortingAlgorithm(algo); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", algo.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckSortingAlgorithm checks algo against slices.Sort on a set of edge cases
+// and on reproducible random inputs, including ones with many duplicates and
+// with values spanning the whole int range. Algorithms that claim stability
+// are also checked for it. It returns nil if every check passes, otherwise an
+// error describing every failure found.
+func CheckSortingAlgorithm(algo SortingAlgorithm) error {
+	var errs []error
+	check := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(sortEdgeCases)) {
+		check(name, CheckSortInput(algo, sortEdgeCases[name]))
+	}
+
+	rng := rand.New(rand.NewSource(sortCheckSeed))
+	for trial := 0; trial < sortCheckTrials; trial++ {
+		input := make([]int, rng.Intn(sortCheckMaxLen+1))
+		var values func() int
+		switch trial % 3 {
+		case 0: // Few distinct values, so plenty of duplicates.
+			values = func() int { return rng.Intn(8) - 4 }
+		case 1:
+			values = func() int { return rng.Intn(2000) - 1000 }
+		default: // The full int range, both signs.
+			values = func() int { return int(rng.Uint64()) }
+		}
+		for i := range input {
+			input[i] = values()
+		}
+		check(fmt.Sprintf("random trial %d (len %d)", trial, len(input)), CheckSortInput(algo, input))
+	}
+
+	if algo.Stable {
+		for trial := 0; trial < sortCheckTrials/10; trial++ {
+			keys := make([]int, rng.Intn(sortCheckMaxLen+1))
+			for i := range keys {
+				keys[i] = rng.Intn(10)
+			}
+			check(fmt.Sprintf("stability trial %d (len %d)", trial, len(keys)), CheckStability(algo, keys))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckSortInput checks that algo sorts a copy of input into the same result as
+// slices.Sort: the output must be in order and a permutation of the input.
+// Counting and comparator forms of the algorithm, when present, must agree. It
+// is the body of the FuzzSort fuzz target.
+func CheckSortInput(algo SortingAlgorithm, input []int) error {
+	want := slices.Clone(input)
+	slices.Sort(want)
+	verify := func(form string, got []int) error {
+		if !slices.IsSorted(got) {
+			return fmt.Errorf("%s: %v sorted to %v, which is out of order", form, input, got)
+		}
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("%s: %v sorted to %v, which is not a permutation of the input", form, input, got)
+		}
+		return nil
+	}
+
+	got := slices.Clone(input)
+	algo.SortFunc(got)
+	if err := verify("SortFunc", got); err != nil {
+		return err
+	}
+	if algo.CountingSortFunc != nil {
+		got = slices.Clone(input)
+		algo.CountingSortFunc(got, &OpCounts{})
+		if err := verify("CountingSortFunc", got); err != nil {
+			return err
+		}
+	}
+	if algo.CompareSortFunc != nil {
+		// Sorting in descending order and reversing must also give want.
+		got = slices.Clone(input)
+		algo.CompareSortFunc(got, Descending(cmp.Compare[int]))
+		slices.Reverse(got)
+		if err := verify("CompareSortFunc descending", got); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckStability checks that algo, sorting the indexes of keys by key, keeps
+// indexes with equal keys in ascending order. algo must have a CompareSortFunc.
+func CheckStability(algo SortingAlgorithm, keys []int) error {
+	if algo.CompareSortFunc == nil {
+		return fmt.Errorf("cannot check stability without a CompareSortFunc")
+	}
+	indexes := make([]int, len(keys))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	algo.CompareSortFunc(indexes, func(a, b int) int { return cmp.Compare(keys[a], keys[b]) })
+	for i := 1; i < len(indexes); i++ {
+		prev, cur := indexes[i-1], indexes[i]
+		if keys[prev] > keys[cur] {
+			return fmt.Errorf("keys %v: index order %v is not sorted by key", keys, indexes)
+		}
+		if keys[prev] == keys[cur] && prev > cur {
+			return fmt.Errorf("keys %v: equal keys at indexes %d and %d were reordered", keys, cur, prev)
+		}
+	}
+	return nil
+}
+
+// CheckFloat64Sort checks sortFunc against slices.Sort on edge cases, including
+// NaNs, infinities and signed zeros, and on reproducible random inputs both
+// inside and outside [0, 1). Results are compared with cmp.Compare, so NaNs
+// must sort first and -0 and +0 count as equal.
+func CheckFloat64Sort(sortFunc func([]float64)) error {
+	inputs := [][]float64{
+		{},
+		{0.5},
+		{0.75, 0.25},
+		{0.5, 0.5, 0.5, 0.5},
+		{math.NaN(), 0.3, math.Inf(1), -0.0, 0, math.Inf(-1), math.NaN(), -2.5, 1, 0.999999999999},
+		{-math.MaxFloat64, math.MaxFloat64, math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64},
+	}
+	rng := rand.New(rand.NewSource(sortCheckSeed))
+	for trial := 0; trial < sortCheckTrials; trial++ {
+		input := make([]float64, rng.Intn(sortCheckMaxLen+1))
+		for i := range input {
+			switch trial % 3 {
+			case 0:
+				input[i] = rng.Float64()
+			case 1: // Bunched up, with duplicates.
+				input[i] = float64(rng.Intn(8)) / 100
+			default:
+				input[i] = rng.NormFloat64() * 1e6
+			}
+		}
+		inputs = append(inputs, input)
+	}
+
+	var errs []error
+	for _, input := range inputs {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		got := slices.Clone(input)
+		sortFunc(got)
+		if slices.CompareFunc(got, want, cmp.Compare[float64]) != 0 {
+			errs = append(errs, fmt.Errorf("%v sorted to %v, want %v", input, got, want))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckStringSort checks sortFunc against slices.Sort on edge cases, such as
+// empty strings, prefixes of one another and high bytes, and on reproducible
+// random inputs with long shared prefixes.
+func CheckStringSort(sortFunc func([]string)) error {
+	inputs := [][]string{
+		{},
+		{"a"},
+		{"b", "a"},
+		{"", "a", "", "ab", "abc", "ab", "a"},
+		{"\xff", "\x00", "z", "\xff\xff", "\x00\x00", "héllo", "hello"},
+	}
+	rng := rand.New(rand.NewSource(sortCheckSeed))
+	for trial := 0; trial < sortCheckTrials; trial++ {
+		input := make([]string, rng.Intn(sortCheckMaxLen+1))
+		prefix := strings.Repeat("p", rng.Intn(20))
+		for i := range input {
+			b := make([]byte, rng.Intn(6))
+			for j := range b {
+				b[j] = "abc"[rng.Intn(3)]
+			}
+			input[i] = prefix + string(b)
+		}
+		inputs = append(inputs, input)
+	}
+
+	var errs []error
+	for _, input := range inputs {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		got := slices.Clone(input)
+		sortFunc(got)
+		if !slices.Equal(got, want) {
+			errs = append(errs, fmt.Errorf("%q sorted to %q, want %q", input, got, want))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TestSortingAlgorithms runs the correctness harness against every registered
+// algorithm, and the float64 and string sorts, and prints the result for each.
+func TestSortingAlgorithms() {
+	for _, algo := range SortingAlgorithms() {
+		if err := CheckSortingAlgorithm(algo); err != nil {
+			fmt.Printf("%s: FAIL\n%v\n", algo.Name, err)
+			continue
+		}
+		stable := ""
+		if algo.Stable {
+			stable = " (stable)"
+		}
+		fmt.Printf("%s: ok%s\n", algo.Name, stable)
+	}
+
+	// The registered parallel sorts never split inputs as small as the
+	// harness's, so check them again with a threshold that makes them split.
+	for _, algo := range parallelAlgorithms(WithParallelThreshold(16), WithMaxParallelism(4)) {
+		if err := CheckSortingAlgorithm(algo); err != nil {
+			fmt.Printf("%s (threshold 16): FAIL\n%v\n", algo.Name, err)
+			continue
+		}
+		fmt.Printf("%s (threshold 16): ok\n", algo.Name)
+	}
+
+	report := func(name string, err error) {
+		if err != nil {
+			fmt.Printf("%s: FAIL\n%v\n", name, err)
+			return
+		}
+		fmt.Printf("%s: ok\n", name)
+	}
+	report("BucketSort", CheckFloat64Sort(BucketSort))
+	report("Float64RadixSort", CheckFloat64Sort(Float64RadixSort))
+	report("MSDRadixSortStrings", CheckStringSort(MSDRadixSortStrings))
+}
@@ -0,0 +1,73 @@
+package algorithms_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	algo "dsa/algorithms"
+)
+
+// fuzzMaxLen bounds the inputs FuzzSort decodes, so the quadratic sorts keep
+// up with the fuzzer.
+const fuzzMaxLen = 4096
+
+func TestSortingAlgorithms(t *testing.T) {
+	for _, a := range algo.SortingAlgorithms() {
+		t.Run(a.Name, func(t *testing.T) {
+			if err := algo.CheckSortingAlgorithm(a); err != nil {
+				t.Error(err)
+			}
+			if a.Stable {
+				keys := []int{3, 1, 2, 1, 3, 3, 0, 2, 1, 0, 0, 2, 3, 1}
+				if err := algo.CheckStability(a, keys); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+// FuzzSort checks every registered algorithm against slices.Sort on inputs
+// decoded from the fuzzer's bytes, eight little-endian bytes per int. Run it
+// with go test -fuzz=FuzzSort ./algorithms.
+func FuzzSort(f *testing.F) {
+	for _, seed := range [][]int{
+		{},
+		{42},
+		{5, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5},
+		{0, math.MinInt, math.MaxInt, -1, math.MinInt, 1},
+		{-5, -2, 0, 1, 1, 3, 8, 13, 21, 34},
+		{34, 21, 13, 8, 3, 1, 1, 0, -2, -5},
+	} {
+		f.Add(encodeInts(seed))
+	}
+	algos := algo.SortingAlgorithms()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		input := decodeInts(data)
+		for _, a := range algos {
+			if err := algo.CheckSortInput(a, input); err != nil {
+				t.Errorf("%s: %v", a.Name, err)
+			}
+		}
+	})
+}
+
+func encodeInts(values []int) []byte {
+	data := make([]byte, 0, 8*len(values))
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint64(data, uint64(v))
+	}
+	return data
+}
+
+// decodeInts decodes up to fuzzMaxLen ints from data, ignoring any bytes left
+// over.
+func decodeInts(data []byte) []int {
+	values := make([]int, 0, min(len(data)/8, fuzzMaxLen))
+	for len(data) >= 8 && len(values) < fuzzMaxLen {
+		values = append(values, int(binary.LittleEndian.Uint64(data)))
+		data = data[8:]
+	}
+	return values
+}
@@ -0,0 +1,182 @@
+package algorithms
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// OpCounts tallies the work a sorting algorithm does, independently of how fast
+// the machine running it is.
+type OpCounts struct {
+	Comparisons int64 `json:"comparisons"` // calls to the comparator
+	Swaps       int64 `json:"swaps"`       // exchanges of two elements
+	// Moves counts single element writes other than swaps: shifting an element
+	// along the array, or copying it into or out of an auxiliary buffer.
+	Moves int64 `json:"moves"`
+	// Allocations counts auxiliary buffers allocated (or grown) while sorting,
+	// and AllocatedElements the total number of elements they hold.
+	Allocations       int64 `json:"allocations"`
+	AllocatedElements int64 `json:"allocated_elements"`
+	MaxDepth          int   `json:"max_depth"` // deepest level of recursion reached
+}
+
+func (c OpCounts) String() string {
+	return fmt.Sprintf("cmp %d | swaps %d | moves %d | allocs %d (%d elems) | depth %d",
+		c.Comparisons, c.Swaps, c.Moves, c.Allocations, c.AllocatedElements, c.MaxDepth)
+}
+
+// sorter carries an algorithm's comparator through its helper functions. When
+// counts is set, the helpers also record every operation in it. When
+// canceled is set, less checks it before every comparison, and sorts that make
+// no comparisons check it once per pass; once it is true, the check abandons
+// the sort by panicking with a sortCanceled, which cancelableSort recovers.
+type sorter[T any] struct {
+	cmp      func(a, b T) int
+	counts   *OpCounts
+	depth    int
+	canceled *atomic.Bool
+}
+
+// sortCanceled is the panic value of a canceled sorter.
+type sortCanceled struct{}
+
+// less reports whether a sorts before b.
+func (s *sorter[T]) less(a, b T) bool {
+	if s.counts != nil {
+		s.counts.Comparisons++
+	}
+	s.checkCanceled()
+	return s.cmp(a, b) < 0
+}
+
+// checkCanceled abandons the sort if s has been canceled.
+func (s *sorter[T]) checkCanceled() {
+	if s.canceled != nil && s.canceled.Load() {
+		panic(sortCanceled{})
+	}
+}
+
+// recoverCanceled, deferred by a goroutine a sort forks, stops a sortCanceled
+// panic from crashing the program and records it in canceled, so that the
+// forking goroutine can panic again once it has waited for the goroutine.
+func recoverCanceled(canceled *atomic.Bool) {
+	if r := recover(); r != nil {
+		if _, ok := r.(sortCanceled); !ok {
+			panic(r)
+		}
+		canceled.Store(true)
+	}
+}
+
+// cancelableSort sets s up to stop once ctx is done, and returns a function
+// that runs sort with s, returning ctx's error if it stopped, and one that
+// releases ctx's hook on s once the sorting is over. The setting up does all
+// the allocating, so it can be kept out of a sort's timing and memory use.
+//
+// A sorter only checks ctx as it compares, or between the passes of a sort
+// that makes no comparisons, so a radix sort finishes the pass it is in. The
+// goroutines of a parallel sort stop along with it, and run returns only once
+// they all have.
+func cancelableSort[T any](ctx context.Context, s *sorter[T], sort func(s *sorter[T], arr []T)) (run func(arr []T) error, release func()) {
+	s.canceled = new(atomic.Bool)
+	stop := context.AfterFunc(ctx, func() { s.canceled.Store(true) })
+	run = func(arr []T) (err error) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(sortCanceled); !ok {
+					panic(r)
+				}
+				err = ctx.Err()
+			}
+		}()
+		sort(s, arr)
+		return nil
+	}
+	return run, func() { stop() }
+}
+
+// swap exchanges arr[i] and arr[j].
+func (s *sorter[T]) swap(arr []T, i, j int) {
+	if s.counts != nil {
+		s.counts.Swaps++
+	}
+	arr[i], arr[j] = arr[j], arr[i]
+}
+
+// moved records n element writes.
+func (s *sorter[T]) moved(n int) {
+	if s.counts != nil {
+		s.counts.Moves += int64(n)
+	}
+}
+
+// allocated records an auxiliary buffer of n elements.
+func (s *sorter[T]) allocated(n int) {
+	if s.counts != nil {
+		s.counts.Allocations++
+		s.counts.AllocatedElements += int64(n)
+	}
+}
+
+// enter and leave bracket a recursive call, tracking the maximum depth.
+func (s *sorter[T]) enter() {
+	if s.counts != nil {
+		s.depth++
+		s.counts.MaxDepth = max(s.counts.MaxDepth, s.depth)
+	}
+}
+
+func (s *sorter[T]) leave() {
+	if s.counts != nil {
+		s.depth--
+	}
+}
+
+// fork returns a sorter for a goroutine that sorts part of the input
+// concurrently with s. Its counts, if any, are kept apart until join adds them
+// to s's, so the goroutines never write the same OpCounts. It is canceled
+// along with s; the goroutine must defer recoverCanceled.
+func (s *sorter[T]) fork() *sorter[T] {
+	child := &sorter[T]{cmp: s.cmp, depth: s.depth, canceled: s.canceled}
+	if s.counts != nil {
+		child.counts = &OpCounts{MaxDepth: s.depth}
+	}
+	return child
+}
+
+// join adds the counts of child, a sorter returned by fork whose goroutine has
+// finished, to s's.
+func (s *sorter[T]) join(child *sorter[T]) {
+	if s.counts == nil {
+		return
+	}
+	c := child.counts
+	s.counts.Comparisons += c.Comparisons
+	s.counts.Swaps += c.Swaps
+	s.counts.Moves += c.Moves
+	s.counts.Allocations += c.Allocations
+	s.counts.AllocatedElements += c.AllocatedElements
+	s.counts.MaxDepth = max(s.counts.MaxDepth, c.MaxDepth)
+}
+
+// makeBuffer allocates an auxiliary buffer of n elements, recording it.
+func makeBuffer[T any](s *sorter[T], n int) []T {
+	s.allocated(n)
+	return make([]T, n)
+}
+
+// appendCounted appends value to buf, recording the append as a move and any
+// reallocation it triggers as an allocation.
+func appendCounted[T, E any](s *sorter[T], buf []E, value E) []E {
+	s.moved(1)
+	if len(buf) == cap(buf) {
+		buf = append(buf, value)
+		s.allocated(cap(buf))
+		return buf
+	}
+	return append(buf, value)
+}
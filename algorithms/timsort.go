@@ -0,0 +1,581 @@
+package algorithms
+
+import "cmp"
+
+const (
+	// timSortMinMerge is the length below which TimSort just runs a binary
+	// insertion sort, and the lower bound on the minimum run length.
+	timSortMinMerge = 32
+	// timSortMinGallop is the initial number of consecutive wins by one run
+	// after which a merge switches to galloping mode.
+	timSortMinGallop = 7
+)
+
+// TimSort
+// TimSort (Tim Peters, 2002) is the adaptive, stable merge sort used by Python
+// and Java. It exploits order that is already present in the input:
+//   - the input is split into runs: maximal non-descending stretches, or strictly descending
+//     ones, which are reversed in place (strictness keeps equal elements in order)
+//   - runs shorter than a minimum length (between 16 and 32, chosen so the number of runs is
+//     close to a power of two) are extended with a binary insertion sort
+//   - runs are pushed on a stack and merged while the stack's lengths would otherwise stop
+//     shrinking geometrically, which keeps merges balanced
+//   - a merge first skips the prefix and suffix already in place, then copies only the
+//     smaller run into a buffer that is reused, and grown, across merges
+//   - when one run keeps winning, the merge switches to galloping: an exponential search
+//     that finds how many elements to copy in one go
+//
+// Already sorted or reversed inputs take O(N) comparisons, and the worst case is
+// O(N log N) with at most N/2 elements of extra memory.
+func TimSort(arr []int) {
+	TimSortFunc(arr, cmp.Compare[int])
+}
+
+// TimSortFunc sorts arr with TimSort in the order defined by cmp. It is stable.
+func TimSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	timSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+// timSortState holds the stack of pending runs and the merge buffer.
+type timSortState[T any] struct {
+	s         *sorter[T]
+	arr       []T
+	tmp       []T
+	minGallop int
+	runBase   []int
+	runLen    []int
+}
+
+func timSort[T any](s *sorter[T], arr []T) {
+	n := len(arr)
+	if n < 2 {
+		return
+	}
+	if n < timSortMinMerge {
+		runLen := countRunAndMakeAscending(s, arr, 0, n)
+		binaryInsertionSort(s, arr, 0, n, runLen)
+		return
+	}
+
+	ts := &timSortState[T]{s: s, arr: arr, minGallop: timSortMinGallop}
+	minRun := timSortMinRun(n)
+	for lo := 0; lo < n; {
+		runLen := countRunAndMakeAscending(s, arr, lo, n)
+		if runLen < minRun {
+			force := min(n-lo, minRun)
+			binaryInsertionSort(s, arr, lo, lo+force, lo+runLen)
+			runLen = force
+		}
+		ts.runBase = append(ts.runBase, lo)
+		ts.runLen = append(ts.runLen, runLen)
+		ts.mergeCollapse()
+		lo += runLen
+	}
+	ts.mergeForceCollapse()
+}
+
+// timSortMinRun returns the minimum run length for n elements: n's top bits,
+// plus one if any lower bit is set, giving a value in [16, 32] that makes
+// n / minRun a power of two or slightly below one.
+func timSortMinRun(n int) int {
+	r := 0
+	for n >= timSortMinMerge {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// countRunAndMakeAscending returns the length of the run starting at arr[lo],
+// reversing it first if it is strictly descending.
+func countRunAndMakeAscending[T any](s *sorter[T], arr []T, lo, hi int) int {
+	runHi := lo + 1
+	if runHi == hi {
+		return 1
+	}
+	if s.less(arr[runHi], arr[lo]) {
+		runHi++
+		for runHi < hi && s.less(arr[runHi], arr[runHi-1]) {
+			runHi++
+		}
+		for i, j := lo, runHi-1; i < j; i, j = i+1, j-1 {
+			s.swap(arr, i, j)
+		}
+	} else {
+		runHi++
+		for runHi < hi && !s.less(arr[runHi], arr[runHi-1]) {
+			runHi++
+		}
+	}
+	return runHi - lo
+}
+
+// binaryInsertionSort sorts arr[lo:hi], given that arr[lo:start] is already
+// sorted, by binary searching each following element's position. Elements are
+// inserted after any equal ones, so the sort is stable.
+func binaryInsertionSort[T any](s *sorter[T], arr []T, lo, hi, start int) {
+	for ; start < hi; start++ {
+		pivot := arr[start]
+		left, right := lo, start
+		for left < right {
+			mid := int(uint(left+right) >> 1)
+			if s.less(pivot, arr[mid]) {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+		copy(arr[left+1:start+1], arr[left:start])
+		arr[left] = pivot
+		s.moved(start - left + 1)
+	}
+}
+
+// mergeCollapse merges runs until the stack's lengths satisfy, from the top:
+// runLen[n-2] > runLen[n-1] + runLen[n] and runLen[n-1] > runLen[n]. This
+// includes the check one level deeper that the original TimSort missed.
+func (ts *timSortState[T]) mergeCollapse() {
+	for len(ts.runLen) > 1 {
+		n := len(ts.runLen) - 2
+		runLen := ts.runLen
+		if (n > 0 && runLen[n-1] <= runLen[n]+runLen[n+1]) || (n > 1 && runLen[n-2] <= runLen[n-1]+runLen[n]) {
+			if runLen[n-1] < runLen[n+1] {
+				n--
+			}
+		} else if runLen[n] > runLen[n+1] {
+			return
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeForceCollapse merges every remaining run, once the input is exhausted.
+func (ts *timSortState[T]) mergeForceCollapse() {
+	for len(ts.runLen) > 1 {
+		n := len(ts.runLen) - 2
+		if n > 0 && ts.runLen[n-1] < ts.runLen[n+1] {
+			n--
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeAt merges the runs at stack positions i and i+1.
+func (ts *timSortState[T]) mergeAt(i int) {
+	s, arr := ts.s, ts.arr
+	base1, len1 := ts.runBase[i], ts.runLen[i]
+	base2, len2 := ts.runBase[i+1], ts.runLen[i+1]
+
+	ts.runLen[i] = len1 + len2
+	last := len(ts.runLen) - 1
+	if i == last-2 {
+		ts.runBase[i+1], ts.runLen[i+1] = ts.runBase[i+2], ts.runLen[i+2]
+	}
+	ts.runBase, ts.runLen = ts.runBase[:last], ts.runLen[:last]
+
+	// Elements of run1 that are <= run2's first element are already in place.
+	k := gallopRight(s, arr[base2], arr, base1, len1, 0)
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+	// So are elements of run2 that are >= run1's last element.
+	len2 = gallopLeft(s, arr[base1+len1-1], arr, base2, len2, len2-1)
+	if len2 == 0 {
+		return
+	}
+	if len1 <= len2 {
+		ts.mergeLo(base1, len1, base2, len2)
+	} else {
+		ts.mergeHi(base1, len1, base2, len2)
+	}
+}
+
+// gallopLeft returns the position in the sorted a[base:base+length] at which
+// key would be inserted before any equal elements, searching outward from hint.
+func gallopLeft[T any](s *sorter[T], key T, a []T, base, length, hint int) int {
+	lastOfs, ofs := 0, 1
+	if s.less(a[base+hint], key) {
+		// Gallop right until a[base+hint+lastOfs] < key <= a[base+hint+ofs].
+		maxOfs := length - hint
+		for ofs < maxOfs && s.less(a[base+hint+ofs], key) {
+			lastOfs = ofs
+			ofs = ofs<<1 + 1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs += hint
+		ofs += hint
+	} else {
+		// Gallop left until a[base+hint-ofs] < key <= a[base+hint-lastOfs].
+		maxOfs := hint + 1
+		for ofs < maxOfs && !s.less(a[base+hint-ofs], key) {
+			lastOfs = ofs
+			ofs = ofs<<1 + 1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs, ofs = hint-ofs, hint-lastOfs
+	}
+	// Binary search a[base+lastOfs+1 : base+ofs] for the exact position.
+	lastOfs++
+	for lastOfs < ofs {
+		m := lastOfs + (ofs-lastOfs)>>1
+		if s.less(a[base+m], key) {
+			lastOfs = m + 1
+		} else {
+			ofs = m
+		}
+	}
+	return ofs
+}
+
+// gallopRight is like gallopLeft, but returns the position after any elements
+// equal to key.
+func gallopRight[T any](s *sorter[T], key T, a []T, base, length, hint int) int {
+	lastOfs, ofs := 0, 1
+	if s.less(key, a[base+hint]) {
+		// Gallop left until a[base+hint-ofs] <= key < a[base+hint-lastOfs].
+		maxOfs := hint + 1
+		for ofs < maxOfs && s.less(key, a[base+hint-ofs]) {
+			lastOfs = ofs
+			ofs = ofs<<1 + 1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs, ofs = hint-ofs, hint-lastOfs
+	} else {
+		// Gallop right until a[base+hint+lastOfs] <= key < a[base+hint+ofs].
+		maxOfs := length - hint
+		for ofs < maxOfs && !s.less(key, a[base+hint+ofs]) {
+			lastOfs = ofs
+			ofs = ofs<<1 + 1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs += hint
+		ofs += hint
+	}
+	lastOfs++
+	for lastOfs < ofs {
+		m := lastOfs + (ofs-lastOfs)>>1
+		if s.less(key, a[base+m]) {
+			ofs = m
+		} else {
+			lastOfs = m + 1
+		}
+	}
+	return ofs
+}
+
+// buffer returns the merge buffer with room for at least n elements. It grows
+// geometrically, but never beyond the half of the input a merge can need.
+func (ts *timSortState[T]) buffer(n int) []T {
+	if len(ts.tmp) < n {
+		ts.tmp = makeBuffer(ts.s, max(n, min(2*len(ts.tmp), len(ts.arr)/2)))
+	}
+	return ts.tmp
+}
+
+// move copies src into dst, recording the writes.
+func (ts *timSortState[T]) move(dst, src []T) {
+	ts.s.moved(copy(dst, src))
+}
+
+// mergeLo merges the adjacent runs arr[base1:base1+len1] and
+// arr[base2:base2+len2], where len1 <= len2, copying run1 into the buffer and
+// filling arr from the left. run1's first element must be greater than run2's
+// first, and run1's last element greater than all of run2.
+func (ts *timSortState[T]) mergeLo(base1, len1, base2, len2 int) {
+	s, arr := ts.s, ts.arr
+	tmp := ts.buffer(len1)
+	ts.move(tmp, arr[base1:base1+len1])
+	cursor1, cursor2, dest := 0, base2, base1
+
+	arr[dest] = arr[cursor2]
+	s.moved(1)
+	dest++
+	cursor2++
+	if len2--; len2 == 0 {
+		ts.move(arr[dest:], tmp[cursor1:cursor1+len1])
+		return
+	}
+	if len1 == 1 {
+		ts.move(arr[dest:], arr[cursor2:cursor2+len2])
+		arr[dest+len2] = tmp[cursor1]
+		s.moved(1)
+		return
+	}
+
+	minGallop := ts.minGallop
+outer:
+	for {
+		count1, count2 := 0, 0 // consecutive wins by run1 and run2
+		// Merge one element at a time until one run starts winning consistently.
+		for (count1 | count2) < minGallop {
+			if s.less(arr[cursor2], tmp[cursor1]) {
+				arr[dest] = arr[cursor2]
+				dest++
+				cursor2++
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 0 {
+					s.moved(1)
+					break outer
+				}
+			} else {
+				arr[dest] = tmp[cursor1]
+				dest++
+				cursor1++
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 1 {
+					s.moved(1)
+					break outer
+				}
+			}
+			s.moved(1)
+		}
+
+		// Gallop until neither run is winning by enough to make it pay off.
+		for {
+			count1 = gallopRight(s, arr[cursor2], tmp, cursor1, len1, 0)
+			if count1 != 0 {
+				ts.move(arr[dest:], tmp[cursor1:cursor1+count1])
+				dest += count1
+				cursor1 += count1
+				len1 -= count1
+				if len1 <= 1 {
+					break outer
+				}
+			}
+			arr[dest] = arr[cursor2]
+			s.moved(1)
+			dest++
+			cursor2++
+			if len2--; len2 == 0 {
+				break outer
+			}
+
+			count2 = gallopLeft(s, tmp[cursor1], arr, cursor2, len2, 0)
+			if count2 != 0 {
+				ts.move(arr[dest:], arr[cursor2:cursor2+count2])
+				dest += count2
+				cursor2 += count2
+				len2 -= count2
+				if len2 == 0 {
+					break outer
+				}
+			}
+			arr[dest] = tmp[cursor1]
+			s.moved(1)
+			dest++
+			cursor1++
+			if len1--; len1 == 1 {
+				break outer
+			}
+			minGallop--
+			if count1 < timSortMinGallop && count2 < timSortMinGallop {
+				break
+			}
+		}
+		// Penalize leaving galloping mode.
+		minGallop = max(minGallop, 0) + 2
+	}
+	ts.minGallop = max(minGallop, 1)
+
+	if len1 == 1 {
+		ts.move(arr[dest:], arr[cursor2:cursor2+len2])
+		arr[dest+len2] = tmp[cursor1]
+		s.moved(1)
+	} else {
+		ts.move(arr[dest:], tmp[cursor1:cursor1+len1])
+	}
+}
+
+// mergeHi is like mergeLo, for len1 >= len2: it copies run2 into the buffer and
+// fills arr from the right.
+func (ts *timSortState[T]) mergeHi(base1, len1, base2, len2 int) {
+	s, arr := ts.s, ts.arr
+	tmp := ts.buffer(len2)
+	ts.move(tmp, arr[base2:base2+len2])
+	cursor1, cursor2, dest := base1+len1-1, len2-1, base2+len2-1
+
+	arr[dest] = arr[cursor1]
+	s.moved(1)
+	dest--
+	cursor1--
+	if len1--; len1 == 0 {
+		ts.move(arr[dest-(len2-1):], tmp[:len2])
+		return
+	}
+	if len2 == 1 {
+		dest -= len1
+		cursor1 -= len1
+		ts.move(arr[dest+1:], arr[cursor1+1:cursor1+1+len1])
+		arr[dest] = tmp[cursor2]
+		s.moved(1)
+		return
+	}
+
+	minGallop := ts.minGallop
+outer:
+	for {
+		count1, count2 := 0, 0 // consecutive wins by run1 and run2
+		for (count1 | count2) < minGallop {
+			if s.less(tmp[cursor2], arr[cursor1]) {
+				arr[dest] = arr[cursor1]
+				dest--
+				cursor1--
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 0 {
+					s.moved(1)
+					break outer
+				}
+			} else {
+				arr[dest] = tmp[cursor2]
+				dest--
+				cursor2--
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 1 {
+					s.moved(1)
+					break outer
+				}
+			}
+			s.moved(1)
+		}
+
+		for {
+			count1 = len1 - gallopRight(s, tmp[cursor2], arr, base1, len1, len1-1)
+			if count1 != 0 {
+				dest -= count1
+				cursor1 -= count1
+				len1 -= count1
+				ts.move(arr[dest+1:], arr[cursor1+1:cursor1+1+count1])
+				if len1 == 0 {
+					break outer
+				}
+			}
+			arr[dest] = tmp[cursor2]
+			s.moved(1)
+			dest--
+			cursor2--
+			if len2--; len2 == 1 {
+				break outer
+			}
+
+			count2 = len2 - gallopLeft(s, arr[cursor1], tmp, 0, len2, len2-1)
+			if count2 != 0 {
+				dest -= count2
+				cursor2 -= count2
+				len2 -= count2
+				ts.move(arr[dest+1:], tmp[cursor2+1:cursor2+1+count2])
+				if len2 <= 1 {
+					break outer
+				}
+			}
+			arr[dest] = arr[cursor1]
+			s.moved(1)
+			dest--
+			cursor1--
+			if len1--; len1 == 0 {
+				break outer
+			}
+			minGallop--
+			if count1 < timSortMinGallop && count2 < timSortMinGallop {
+				break
+			}
+		}
+		minGallop = max(minGallop, 0) + 2
+	}
+	ts.minGallop = max(minGallop, 1)
+
+	if len2 == 1 {
+		dest -= len1
+		cursor1 -= len1
+		ts.move(arr[dest+1:], arr[cursor1+1:cursor1+1+len1])
+		arr[dest] = tmp[cursor2]
+		s.moved(1)
+	} else {
+		ts.move(arr[dest-(len2-1):], tmp[:len2])
+	}
+}
+
+// NaturalMergeSort
+// A bottom-up merge sort that starts from the runs already present in the input
+// instead of from single elements:
+//   - one pass splits the input into maximal non-descending runs, reversing strictly
+//     descending ones in place
+//   - each following pass merges neighbouring pairs of runs, halving their number
+//
+// With R initial runs that is ⌈log2 R⌉ passes of N moves each: O(N) for sorted or
+// reversed input and O(N log N) in the worst case. Merges copy only the left run
+// out, into a single buffer allocated once.
+func NaturalMergeSort(arr []int) {
+	NaturalMergeSortFunc(arr, cmp.Compare[int])
+}
+
+// NaturalMergeSortFunc sorts arr with NaturalMergeSort in the order defined by
+// cmp. It is stable.
+func NaturalMergeSortFunc[T any](arr []T, cmp func(a, b T) int) {
+	naturalMergeSort(&sorter[T]{cmp: cmp}, arr)
+}
+
+func naturalMergeSort[T any](s *sorter[T], arr []T) {
+	n := len(arr)
+	if n < 2 {
+		return
+	}
+	// runEnds[i] is the index just past the end of run i.
+	var runEnds []int
+	for lo := 0; lo < n; {
+		lo += countRunAndMakeAscending(s, arr, lo, n)
+		runEnds = append(runEnds, lo)
+	}
+	if len(runEnds) == 1 {
+		return
+	}
+
+	var buf []T
+	for len(runEnds) > 1 {
+		merged := runEnds[:0]
+		start := 0
+		for i := 0; i < len(runEnds); i += 2 {
+			if i+1 == len(runEnds) {
+				merged = append(merged, runEnds[i])
+				break
+			}
+			mid, end := runEnds[i], runEnds[i+1]
+			if buf == nil {
+				buf = makeBuffer(s, n)
+			}
+			mergeRuns(s, arr[start:end], mid-start, buf)
+			merged = append(merged, end)
+			start = end
+		}
+		runEnds = merged
+	}
+}
+
+// mergeRuns merges the sorted arr[:mid] and arr[mid:] in place, stably, using
+// buf to hold the left run.
+func mergeRuns[T any](s *sorter[T], arr []T, mid int, buf []T) {
+	left := buf[:mid]
+	s.moved(copy(left, arr[:mid]))
+	i, j, k := 0, mid, 0
+	for i < len(left) && j < len(arr) {
+		if s.less(arr[j], left[i]) {
+			arr[k] = arr[j]
+			j++
+		} else {
+			arr[k] = left[i]
+			i++
+		}
+		k++
+	}
+	// Whatever remains of the right run is already in place.
+	s.moved(k + copy(arr[k:], left[i:]))
+}
@@ -0,0 +1,148 @@
+package datastructures
+
+// ArrayDeque is a Deque backed by a growable circular buffer, modelled on Python's
+// collections.deque. Pushes and pops at either end are O(1) amortized, and At/Set
+// are O(1) because an element's position maps directly to a slot in the buffer.
+type ArrayDeque[T any] struct {
+	items  ring[T]
+	maxLen int
+}
+
+// NewArrayDeque creates a new empty array-backed deque.
+func NewArrayDeque[T any](opts ...DequeOption) *ArrayDeque[T] {
+	return &ArrayDeque[T]{maxLen: applyDequeOptions(opts).maxLen}
+}
+
+// PushFront adds an element to the front of the deque, evicting the back
+// element if the deque is full.
+func (d *ArrayDeque[T]) PushFront(value T) {
+	if d.isFull() {
+		d.items.popBack()
+	}
+	d.items.pushFront(value)
+}
+
+// PushBack adds an element to the back of the deque, evicting the front
+// element if the deque is full.
+func (d *ArrayDeque[T]) PushBack(value T) {
+	if d.isFull() {
+		d.items.popFront()
+	}
+	d.items.pushBack(value)
+}
+
+// PopFront removes and returns the element from the front of the deque.
+func (d *ArrayDeque[T]) PopFront() (T, bool) {
+	if d.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return d.items.popFront(), true
+}
+
+// PopBack removes and returns the element from the back of the deque.
+func (d *ArrayDeque[T]) PopBack() (T, bool) {
+	if d.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return d.items.popBack(), true
+}
+
+// PeekFront returns the element at the front of the deque without removing it.
+func (d *ArrayDeque[T]) PeekFront() (T, bool) {
+	return d.peek(0)
+}
+
+// PeekBack returns the element at the back of the deque without removing it.
+func (d *ArrayDeque[T]) PeekBack() (T, bool) {
+	return d.peek(d.items.len() - 1)
+}
+
+func (d *ArrayDeque[T]) peek(i int) (T, bool) {
+	value, err := d.At(i)
+	return value, err == nil
+}
+
+// At returns the element i positions from the front.
+func (d *ArrayDeque[T]) At(i int) (T, error) {
+	if i < 0 || i >= d.items.len() {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	return d.items.at(i), nil
+}
+
+// Set replaces the element i positions from the front.
+func (d *ArrayDeque[T]) Set(i int, value T) error {
+	if i < 0 || i >= d.items.len() {
+		return ErrIndexOutOfRange
+	}
+	d.items.set(i, value)
+	return nil
+}
+
+// Rotate moves the last k elements to the front (k > 0) or the first -k elements
+// to the back (k < 0). It is O(1) when the buffer is full and O(min(k, n-k))
+// otherwise.
+func (d *ArrayDeque[T]) Rotate(k int) {
+	d.items.rotate(k)
+}
+
+// Reverse reverses the order of the elements in place.
+func (d *ArrayDeque[T]) Reverse() {
+	d.items.reverse()
+}
+
+// Extend pushes each value onto the back of the deque, in order.
+func (d *ArrayDeque[T]) Extend(values ...T) {
+	for _, value := range values {
+		d.PushBack(value)
+	}
+}
+
+// ExtendFront pushes each value onto the front of the deque, in order.
+func (d *ArrayDeque[T]) ExtendFront(values ...T) {
+	for _, value := range values {
+		d.PushFront(value)
+	}
+}
+
+// Clear removes every element and releases the deque's buffer.
+func (d *ArrayDeque[T]) Clear() {
+	d.items.clear()
+}
+
+// IsEmpty returns true if the deque is empty, false otherwise.
+func (d *ArrayDeque[T]) IsEmpty() bool {
+	return d.items.len() == 0
+}
+
+// GetLength returns the number of elements in the deque.
+func (d *ArrayDeque[T]) GetLength() int {
+	return d.items.len()
+}
+
+// MaxLen returns the deque's bound, or 0 if it is unbounded.
+func (d *ArrayDeque[T]) MaxLen() int {
+	return d.maxLen
+}
+
+func (d *ArrayDeque[T]) isFull() bool {
+	return d.maxLen > 0 && d.items.len() >= d.maxLen
+}
+
+// Iterator returns an iterator over the elements from front to back.
+func (d *ArrayDeque[T]) Iterator() Iterator[T] {
+	return indexIterator(d.items.len(), false, d.items.at)
+}
+
+// ReverseIterator returns an iterator over the elements from back to front.
+func (d *ArrayDeque[T]) ReverseIterator() Iterator[T] {
+	return indexIterator(d.items.len(), true, d.items.at)
+}
+
+// Print prints the elements of the deque from front to back.
+func (d *ArrayDeque[T]) Print() {
+	printDeque[T](d)
+}
@@ -1,90 +1,217 @@
 package datastructures
 
-import "fmt"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+)
 
-type ArrayList struct {
-	data     []ArrayListData
-	capacity int
-	size     int
+// ErrIndexOutOfRange is returned by indexed list operations when the index
+// (or range) falls outside the list's current bounds.
+var ErrIndexOutOfRange = errors.New("index out of range")
+
+// defaultArrayListCapacity is the initial allocation of a new ArrayList.
+const defaultArrayListCapacity = 5
+
+// ArrayList is a dynamic array: an ordered list of items that allows indexed access
+// and grows (doubling its allocation) as items are added.
+//
+//   - Append:   O(1) amortized
+//   - Prepend:  O(n), all items shift one position to the right
+//   - Get/Set:  O(1)
+//   - Insert:   O(n), items after the index shift right
+//   - RemoveAt: O(n), items after the index shift left
+type ArrayList[T any] struct {
+	data []T
+	size int
 }
 
-type ArrayListData interface{}
+// NewArrayList creates a new empty array list with the default initial capacity.
+func NewArrayList[T any]() *ArrayList[T] {
+	return NewArrayListWithCapacity[T](defaultArrayListCapacity)
+}
 
-func NewArrayList() *ArrayList {
-	return &ArrayList{
-		data:     make([]ArrayListData, 5), // Initial capacity of 10, you can change it as needed
-		capacity: 5,
-		size:     0,
+// NewArrayListWithCapacity creates a new empty array list with room for capacity items.
+func NewArrayListWithCapacity[T any](capacity int) *ArrayList[T] {
+	if capacity < 0 {
+		capacity = 0
 	}
+	return &ArrayList[T]{data: make([]T, capacity)}
 }
 
-func (list *ArrayList) ArrayListAppend(value ArrayListData) {
-	if list.size == list.capacity {
-		list.ArrayListResize(list.capacity * 2)
-	}
+// Len returns the number of items stored in the list.
+func (list *ArrayList[T]) Len() int {
+	return list.size
+}
 
-	list.data[list.size] = value
-	list.size++
+// Cap returns the number of items the list can hold before it must reallocate.
+func (list *ArrayList[T]) Cap() int {
+	return len(list.data)
 }
 
-func (list *ArrayList) ArrayListResize(newCapacity int) {
-	newData := make([]ArrayListData, newCapacity)
-	copy(newData, list.data)
-	list.data = newData
-	list.capacity = newCapacity
+// IsEmpty returns true if the list holds no items.
+func (list *ArrayList[T]) IsEmpty() bool {
+	return list.size == 0
 }
 
-func (list *ArrayList) ArrayListPrepend(value ArrayListData) {
-	if list.size == list.capacity {
-		list.ArrayListResize(list.capacity * 2)
+// Get returns the item stored at index.
+func (list *ArrayList[T]) Get(index int) (T, error) {
+	if index < 0 || index >= list.size {
+		var zero T
+		return zero, ErrIndexOutOfRange
 	}
+	return list.data[index], nil
+}
+
+// Set replaces the item stored at index.
+func (list *ArrayList[T]) Set(index int, value T) error {
+	if index < 0 || index >= list.size {
+		return ErrIndexOutOfRange
+	}
+	list.data[index] = value
+	return nil
+}
 
-	copy(list.data[1:], list.data[:list.size])
-	list.data[0] = value
+// Append adds values to the end of the list.
+func (list *ArrayList[T]) Append(values ...T) {
+	list.grow(len(values))
+	copy(list.data[list.size:], values)
+	list.size += len(values)
+}
+
+// Prepend adds a value to the front of the list.
+func (list *ArrayList[T]) Prepend(value T) {
+	_ = list.Insert(0, value)
+}
+
+// Insert places value at index, shifting the item currently at index (and every
+// item after it) one position to the right. An index equal to Len appends.
+func (list *ArrayList[T]) Insert(index int, value T) error {
+	if index < 0 || index > list.size {
+		return ErrIndexOutOfRange
+	}
+	list.grow(1)
+	copy(list.data[index+1:list.size+1], list.data[index:list.size])
+	list.data[index] = value
 	list.size++
+	return nil
 }
 
-func (list *ArrayList) ArrayListInsertAfter(value ArrayListData, index int) error {
+// RemoveAt removes and returns the item stored at index.
+func (list *ArrayList[T]) RemoveAt(index int) (T, error) {
 	if index < 0 || index >= list.size {
-		return fmt.Errorf("index out of range")
+		var zero T
+		return zero, ErrIndexOutOfRange
 	}
+	value := list.data[index]
+	_ = list.RemoveRange(index, index+1)
+	return value, nil
+}
 
-	if list.size == list.capacity {
-		list.ArrayListResize(list.capacity * 2)
+// RemoveRange removes the items in the half-open range [from, to).
+func (list *ArrayList[T]) RemoveRange(from, to int) error {
+	if from < 0 || to > list.size || from > to {
+		return ErrIndexOutOfRange
 	}
+	copy(list.data[from:], list.data[to:list.size])
+	newSize := list.size - (to - from)
+	clear(list.data[newSize:list.size]) // Zero out the vacated slots so they can be collected
+	list.size = newSize
 
-	copy(list.data[index+2:], list.data[index+1:list.size+1])
-	list.data[index+1] = value
-	list.size++
+	if list.size <= len(list.data)/4 && len(list.data) > 10 {
+		list.resize(len(list.data) / 2)
+	}
 	return nil
 }
 
-func (list *ArrayList) ArrayListSearch(value ArrayListData) int {
+// Slice returns a copy of the items in the half-open range [from, to).
+func (list *ArrayList[T]) Slice(from, to int) ([]T, error) {
+	if from < 0 || to > list.size || from > to {
+		return nil, ErrIndexOutOfRange
+	}
+	out := make([]T, to-from)
+	copy(out, list.data[from:to])
+	return out, nil
+}
+
+// ToSlice returns a copy of every item in the list, in order.
+func (list *ArrayList[T]) ToSlice() []T {
+	out, _ := list.Slice(0, list.size)
+	return out
+}
+
+// Clear removes every item from the list while keeping its allocation.
+func (list *ArrayList[T]) Clear() {
+	clear(list.data[:list.size])
+	list.size = 0
+}
+
+// Reserve ensures the list can hold at least capacity items without reallocating.
+func (list *ArrayList[T]) Reserve(capacity int) {
+	if capacity > len(list.data) {
+		list.resize(capacity)
+	}
+}
+
+// ShrinkToFit releases any allocation beyond the items currently stored.
+func (list *ArrayList[T]) ShrinkToFit() {
+	if len(list.data) > list.size {
+		list.resize(list.size)
+	}
+}
+
+// Range calls fn for each item in order until fn returns false.
+func (list *ArrayList[T]) Range(fn func(index int, value T) bool) {
 	for i := 0; i < list.size; i++ {
-		if list.data[i] == value {
+		if !fn(i, list.data[i]) {
+			return
+		}
+	}
+}
+
+// Iterator returns an iterator over the items from index 0 to Len-1.
+func (list *ArrayList[T]) Iterator() Iterator[T] {
+	return indexIterator(list.size, false, func(i int) T { return list.data[i] })
+}
+
+// ReverseIterator returns an iterator over the items from index Len-1 to 0.
+func (list *ArrayList[T]) ReverseIterator() Iterator[T] {
+	return indexIterator(list.size, true, func(i int) T { return list.data[i] })
+}
+
+// Search returns the index of the first item for which compare(item, target)
+// reports 0, or -1 if no such item exists. compare follows the cmp.Compare
+// convention, so cmp.Compare can be passed directly for ordered types.
+func (list *ArrayList[T]) Search(target T, compare func(a, b T) int) int {
+	for i := 0; i < list.size; i++ {
+		if compare(list.data[i], target) == 0 {
 			return i
 		}
 	}
 	return -1
 }
 
-func (list *ArrayList) ArrayListRemoveAt(index int) error {
-	if index < 0 || index >= list.size {
-		return fmt.Errorf("index out of range")
+// grow makes room for n more items, doubling the allocation as needed.
+func (list *ArrayList[T]) grow(n int) {
+	needed := list.size + n
+	if needed <= len(list.data) {
+		return
 	}
-
-	copy(list.data[index:], list.data[index+1:list.size])
-	list.data[list.size-1] = nil // Zero out the last element
-	list.size--
-
-	if list.size <= list.capacity/4 && list.capacity > 10 {
-		list.ArrayListResize(list.capacity / 2)
+	newCapacity := max(len(list.data)*2, 1)
+	for newCapacity < needed {
+		newCapacity *= 2
 	}
+	list.resize(newCapacity)
+}
 
-	return nil
+// resize reallocates the backing array to exactly newCapacity slots.
+func (list *ArrayList[T]) resize(newCapacity int) {
+	newData := make([]T, newCapacity)
+	copy(newData, list.data[:list.size])
+	list.data = newData
 }
 
-func (list *ArrayList) Print() {
+func (list *ArrayList[T]) Print() {
 	fmt.Print("[")
 	for i := 0; i < list.size; i++ {
 		fmt.Print(list.data[i])
@@ -95,29 +222,35 @@ func (list *ArrayList) Print() {
 	fmt.Println("]")
 }
 
-func TestArrayList(initData []ArrayListData) {
-	list := NewArrayList()
-	for _, data := range initData {
-		list.ArrayListAppend(data)
-	}
+func TestArrayList(initData []int) {
+	compare := cmp.Compare[int]
+	list := NewArrayList[int]()
+	list.Append(initData...)
 	fmt.Printf("initial: ")
 	list.Print()
-	fmt.Printf("allocation size: %d\n", list.capacity)
-	fmt.Printf("array list length: %d\n", list.size)
+	fmt.Printf("allocation size: %d\n", list.Cap())
+	fmt.Printf("array list length: %d\n", list.Len())
 
-	indx := list.ArrayListSearch(26)
+	indx := list.Search(26, compare)
 	fmt.Printf("arry search -> 26 ([%d]) -> list: ", indx)
 	list.Print()
 
-	_ = list.ArrayListRemoveAt(0)
+	_, _ = list.RemoveAt(0)
 	fmt.Printf("array remove -> index 0 -> list: ")
 	list.Print()
-	indx = list.ArrayListSearch(26)
+	indx = list.Search(26, compare)
 	fmt.Printf("arry search -> 26 ([%d]) -> list: ", indx)
 	list.Print()
 
-	indx = list.ArrayListSearch(88)
+	indx = list.Search(88, compare)
 	fmt.Printf("arry search -> 88 ([%d]) -> list: ", indx)
 	list.Print()
 
+	// Fill the list to capacity, then insert in the middle to exercise growth.
+	for list.Len() < list.Cap() {
+		list.Append(list.Len())
+	}
+	_ = list.Insert(1, 99)
+	fmt.Printf("array insert -> 99 @ [1] -> list: ")
+	list.Print()
 }
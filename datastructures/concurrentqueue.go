@@ -0,0 +1,335 @@
+package datastructures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrQueueClosed is returned when enqueuing to a closed ConcurrentQueue, or when
+// dequeuing from one that is closed and fully drained.
+var ErrQueueClosed = errors.New("queue is closed")
+
+// ConcurrentQueue is a FIFO queue that is safe for use by multiple goroutines.
+// It wraps a Queue with a mutex and lets producers and consumers block until
+// there is room or an item, respectively, or until their context is done.
+//
+// Closing the queue wakes every blocked caller: producers get ErrQueueClosed,
+// while consumers keep receiving the remaining items and get ErrQueueClosed only
+// once the queue is empty.
+type ConcurrentQueue[T any] struct {
+	mu     sync.Mutex
+	items  *Queue[T]
+	closed bool
+	// notFull and notEmpty are closed to wake the producers waiting for room and
+	// the consumers waiting for an item, respectively. Each is made only when a
+	// goroutine starts waiting on it and is nil while nobody is. Unlike
+	// sync.Cond, waiting on a channel can be combined with ctx.Done().
+	notFull  chan struct{}
+	notEmpty chan struct{}
+}
+
+// NewConcurrentQueue creates a new empty concurrent queue. A capacity <= 0 makes
+// the queue unbounded; otherwise Enqueue blocks while capacity items are queued.
+func NewConcurrentQueue[T any](capacity int) *ConcurrentQueue[T] {
+	return &ConcurrentQueue[T]{
+		items: NewQueue[T](WithCapacity(capacity, RejectWhenFull)),
+	}
+}
+
+// waitOn returns the channel that will be closed when signal is next called on
+// *ch, making it if nobody is waiting yet. The caller must hold the queue's lock.
+func waitOn(ch *chan struct{}) <-chan struct{} {
+	if *ch == nil {
+		*ch = make(chan struct{})
+	}
+	return *ch
+}
+
+// signal wakes every goroutine waiting on *ch, if any. The caller must hold the
+// queue's lock.
+func signal(ch *chan struct{}) {
+	if *ch != nil {
+		close(*ch)
+		*ch = nil
+	}
+}
+
+// Enqueue adds v to the end of the queue, blocking while the queue is full.
+// It returns ctx.Err() if ctx is done first, or ErrQueueClosed if the queue is closed.
+func (q *ConcurrentQueue[T]) Enqueue(ctx context.Context, v T) error {
+	for {
+		q.mu.Lock()
+		err := q.tryEnqueueLocked(v)
+		var wait <-chan struct{}
+		if errors.Is(err, ErrQueueFull) {
+			wait = waitOn(&q.notFull)
+		}
+		q.mu.Unlock()
+		if wait == nil {
+			return err
+		}
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryEnqueue adds v to the end of the queue without blocking. It returns
+// ErrQueueFull if the queue is at capacity, or ErrQueueClosed if it is closed.
+func (q *ConcurrentQueue[T]) TryEnqueue(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.tryEnqueueLocked(v)
+}
+
+func (q *ConcurrentQueue[T]) tryEnqueueLocked(v T) error {
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if err := q.items.Enqueue(v); err != nil {
+		return err
+	}
+	signal(&q.notEmpty)
+	return nil
+}
+
+// Dequeue removes and returns the front item, blocking while the queue is empty.
+// It returns ctx.Err() if ctx is done first, or ErrQueueClosed once the queue is
+// closed and drained.
+func (q *ConcurrentQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		v, err := q.tryDequeueLocked()
+		var wait <-chan struct{}
+		if errors.Is(err, errQueueEmpty) {
+			wait = waitOn(&q.notEmpty)
+		}
+		q.mu.Unlock()
+		if wait == nil {
+			return v, err
+		}
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// errQueueEmpty is the internal signal that a dequeue must wait.
+var errQueueEmpty = errors.New("queue is empty")
+
+func (q *ConcurrentQueue[T]) tryDequeueLocked() (T, error) {
+	v, ok := q.items.Dequeue()
+	if !ok {
+		if q.closed {
+			return v, ErrQueueClosed
+		}
+		return v, errQueueEmpty
+	}
+	signal(&q.notFull)
+	return v, nil
+}
+
+// TryDequeue removes and returns the front item without blocking.
+// It returns false if the queue is empty.
+func (q *ConcurrentQueue[T]) TryDequeue() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	v, err := q.tryDequeueLocked()
+	return v, err == nil
+}
+
+// DequeueBatch removes and returns up to limit items from the front of the queue,
+// blocking until at least one item is available. A limit <= 0 takes every queued
+// item. It returns ctx.Err() if ctx is done first, or ErrQueueClosed once the
+// queue is closed and drained.
+func (q *ConcurrentQueue[T]) DequeueBatch(ctx context.Context, limit int) ([]T, error) {
+	for {
+		q.mu.Lock()
+		batch := q.drainLocked(limit)
+		closed := q.closed
+		var wait <-chan struct{}
+		if len(batch) == 0 && !closed {
+			wait = waitOn(&q.notEmpty)
+		}
+		q.mu.Unlock()
+		if len(batch) > 0 {
+			return batch, nil
+		}
+		if closed {
+			return nil, ErrQueueClosed
+		}
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Drain removes and returns up to limit items from the front of the queue without
+// blocking. A limit <= 0 drains every queued item.
+func (q *ConcurrentQueue[T]) Drain(limit int) []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.drainLocked(limit)
+}
+
+func (q *ConcurrentQueue[T]) drainLocked(limit int) []T {
+	n := q.items.GetLength()
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]T, n)
+	for i := range out {
+		out[i], _ = q.items.Dequeue()
+	}
+	signal(&q.notFull)
+	return out
+}
+
+// Close marks the queue closed and wakes every blocked caller. Items already
+// queued can still be dequeued. Closing an already closed queue is a no-op.
+func (q *ConcurrentQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		signal(&q.notFull)
+		signal(&q.notEmpty)
+	}
+}
+
+// IsClosed returns true once Close has been called.
+func (q *ConcurrentQueue[T]) IsClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// Len returns the number of items currently queued.
+func (q *ConcurrentQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.GetLength()
+}
+
+// TestConcurrentQueue is a stress test: producers concurrently enqueue items
+// (half of them blocking, half retrying TryEnqueue) into a small bounded queue
+// while consumers dequeue them (half one at a time, half in batches), then it
+// checks that every item was received exactly once. Run it under the race
+// detector (go run -race) to also check for data races.
+func TestConcurrentQueue(producers, consumers, itemsPerProducer, capacity int) error {
+	q := NewConcurrentQueue[int](capacity)
+	ctx := context.Background()
+	total := producers * itemsPerProducer
+	seen := make([]atomic.Int32, total)
+	errs := make(chan error, producers+consumers)
+
+	var producerWG sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producerWG.Add(1)
+		go func(p int) {
+			defer producerWG.Done()
+			for i := 0; i < itemsPerProducer; i++ {
+				item := p*itemsPerProducer + i
+				if p%2 == 0 {
+					if err := q.Enqueue(ctx, item); err != nil {
+						errs <- fmt.Errorf("producer %d: Enqueue(%d): %w", p, item, err)
+						return
+					}
+					continue
+				}
+				err := q.TryEnqueue(item)
+				for errors.Is(err, ErrQueueFull) {
+					runtime.Gosched()
+					err = q.TryEnqueue(item)
+				}
+				if err != nil {
+					errs <- fmt.Errorf("producer %d: TryEnqueue(%d): %w", p, item, err)
+					return
+				}
+			}
+		}(p)
+	}
+
+	var consumerWG sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		consumerWG.Add(1)
+		go func(c int) {
+			defer consumerWG.Done()
+			for {
+				if c%2 == 0 {
+					item, err := q.Dequeue(ctx)
+					if errors.Is(err, ErrQueueClosed) {
+						return
+					}
+					if err != nil {
+						errs <- fmt.Errorf("consumer %d: Dequeue: %w", c, err)
+						return
+					}
+					seen[item].Add(1)
+					continue
+				}
+				batch, err := q.DequeueBatch(ctx, capacity)
+				if errors.Is(err, ErrQueueClosed) {
+					return
+				}
+				if err != nil {
+					errs <- fmt.Errorf("consumer %d: DequeueBatch: %w", c, err)
+					return
+				}
+				for _, item := range batch {
+					seen[item].Add(1)
+				}
+			}
+		}(c)
+	}
+
+	producerWG.Wait()
+	q.Close()
+	consumerWG.Wait()
+	close(errs)
+	var failures []error
+	for err := range errs {
+		failures = append(failures, err)
+	}
+	if len(failures) > 0 {
+		return errors.Join(failures...)
+	}
+
+	if err := q.Enqueue(ctx, -1); !errors.Is(err, ErrQueueClosed) {
+		return fmt.Errorf("Enqueue after Close: err = %v, want ErrQueueClosed", err)
+	}
+	for item := range seen {
+		if n := seen[item].Load(); n != 1 {
+			return fmt.Errorf("item %d received %d times, want 1", item, n)
+		}
+	}
+
+	// A consumer blocked on an empty queue must return when its context is cancelled.
+	cancelled, cancel := context.WithCancel(ctx)
+	blocked := NewConcurrentQueue[int](capacity)
+	done := make(chan error)
+	go func() {
+		_, err := blocked.Dequeue(cancelled)
+		done <- err
+	}()
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("Dequeue with cancelled context: err = %v, want context.Canceled", err)
+	}
+	fmt.Printf("concurrent queue: %d producers -> %d consumers, %d items ok\n", producers, consumers, total)
+	return nil
+}
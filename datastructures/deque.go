@@ -2,160 +2,394 @@ package datastructures
 
 import "fmt"
 
-type DequeValue interface{}
+// Deque is a double-ended queue: items can be added or removed at either end.
+// ArrayDeque (a circular buffer, O(1) indexed access) and LinkedDeque (a doubly
+// linked list) both implement it.
+//
+// A deque created with WithMaxLen holds at most that many items; pushing onto a
+// full deque evicts an item from the opposite end, like Python's collections.deque.
+type Deque[T any] interface {
+	// PushFront adds an element to the front of the deque.
+	PushFront(value T)
+	// PushBack adds an element to the back of the deque.
+	PushBack(value T)
+	// PopFront removes and returns the front element, or false if the deque is empty.
+	PopFront() (T, bool)
+	// PopBack removes and returns the back element, or false if the deque is empty.
+	PopBack() (T, bool)
+	// PeekFront returns the front element, or false if the deque is empty.
+	PeekFront() (T, bool)
+	// PeekBack returns the back element, or false if the deque is empty.
+	PeekBack() (T, bool)
+	// At returns the element i positions from the front.
+	At(i int) (T, error)
+	// Set replaces the element i positions from the front.
+	Set(i int, value T) error
+	// Rotate moves the last k elements to the front (k > 0) or the first -k
+	// elements to the back (k < 0).
+	Rotate(k int)
+	// Reverse reverses the order of the elements in place.
+	Reverse()
+	// Extend pushes each value onto the back, in order.
+	Extend(values ...T)
+	// ExtendFront pushes each value onto the front, in order, so the values end
+	// up in reverse order at the front of the deque.
+	ExtendFront(values ...T)
+	// Clear removes every element.
+	Clear()
+	// IsEmpty returns true if the deque is empty.
+	IsEmpty() bool
+	// GetLength returns the number of elements in the deque.
+	GetLength() int
+	// MaxLen returns the deque's bound, or 0 if it is unbounded.
+	MaxLen() int
+	// Iterator returns an iterator over the elements from front to back.
+	Iterator() Iterator[T]
+	// ReverseIterator returns an iterator over the elements from back to front.
+	ReverseIterator() Iterator[T]
+}
+
+var (
+	_ Deque[int] = (*ArrayDeque[int])(nil)
+	_ Deque[int] = (*LinkedDeque[int])(nil)
+)
+
+// DequeOption configures a deque created by NewArrayDeque or NewLinkedDeque.
+type DequeOption func(*dequeOptions)
+
+type dequeOptions struct {
+	maxLen int
+}
 
-// DequeNode represents an element in the deque.
-type DequeNode struct {
-	Value DequeValue
-	Prev  *DequeNode
-	Next  *DequeNode
+// WithMaxLen bounds the deque to maxLen elements. A maxLen <= 0 leaves it unbounded.
+func WithMaxLen(maxLen int) DequeOption {
+	return func(o *dequeOptions) {
+		o.maxLen = max(maxLen, 0)
+	}
+}
+
+func applyDequeOptions(opts []DequeOption) dequeOptions {
+	var o dequeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
-// Deque represents a double-ended queue.
-type Deque struct {
-	Front *DequeNode
-	Back  *DequeNode
-	Size  int
+// DequeNode represents an element in a LinkedDeque.
+type DequeNode[T any] struct {
+	value T
+	prev  *DequeNode[T]
+	next  *DequeNode[T]
 }
 
-// NewDeque creates a new empty deque.
-func NewDeque() *Deque {
-	return &Deque{}
+// LinkedDeque is a Deque backed by a doubly linked list. Operations at either
+// end are O(1); indexed access walks from the nearer end and is O(n).
+type LinkedDeque[T any] struct {
+	front  *DequeNode[T]
+	back   *DequeNode[T]
+	size   int
+	maxLen int
 }
 
-// PushFront adds an element to the front of the deque.
-func (d *Deque) PushFront(value DequeValue) {
-	newDequeNode := &DequeNode{Value: value}
+// NewLinkedDeque creates a new empty linked deque.
+func NewLinkedDeque[T any](opts ...DequeOption) *LinkedDeque[T] {
+	return &LinkedDeque[T]{maxLen: applyDequeOptions(opts).maxLen}
+}
+
+// PushFront adds an element to the front of the deque, evicting the back
+// element if the deque is full.
+func (d *LinkedDeque[T]) PushFront(value T) {
+	if d.isFull() {
+		d.PopBack()
+	}
+	newDequeNode := &DequeNode[T]{value: value}
 	if d.IsEmpty() {
-		d.Front = newDequeNode
-		d.Back = newDequeNode
+		d.front = newDequeNode
+		d.back = newDequeNode
 	} else {
-		newDequeNode.Next = d.Front
-		d.Front.Prev = newDequeNode
-		d.Front = newDequeNode
+		newDequeNode.next = d.front
+		d.front.prev = newDequeNode
+		d.front = newDequeNode
 	}
-	d.Size++
+	d.size++
 }
 
-// PushBack adds an element to the back of the deque.
-func (d *Deque) PushBack(value DequeValue) {
-	newDequeNode := &DequeNode{Value: value}
+// PushBack adds an element to the back of the deque, evicting the front
+// element if the deque is full.
+func (d *LinkedDeque[T]) PushBack(value T) {
+	if d.isFull() {
+		d.PopFront()
+	}
+	newDequeNode := &DequeNode[T]{value: value}
 	if d.IsEmpty() {
-		d.Front = newDequeNode
-		d.Back = newDequeNode
+		d.front = newDequeNode
+		d.back = newDequeNode
 	} else {
-		newDequeNode.Prev = d.Back
-		d.Back.Next = newDequeNode
-		d.Back = newDequeNode
+		newDequeNode.prev = d.back
+		d.back.next = newDequeNode
+		d.back = newDequeNode
 	}
-	d.Size++
+	d.size++
 }
 
 // PopFront removes and returns the element from the front of the deque.
-func (d *Deque) PopFront() DequeValue {
+func (d *LinkedDeque[T]) PopFront() (T, bool) {
 	if d.IsEmpty() {
-		return nil
+		var zero T
+		return zero, false
 	}
-	value := d.Front.Value
-	if d.Front == d.Back {
-		d.Front = nil
-		d.Back = nil
+	value := d.front.value
+	if d.front == d.back {
+		d.front = nil
+		d.back = nil
 	} else {
-		d.Front = d.Front.Next
-		d.Front.Prev = nil
+		d.front = d.front.next
+		d.front.prev = nil
 	}
-	d.Size--
-	return value
+	d.size--
+	return value, true
 }
 
 // PopBack removes and returns the element from the back of the deque.
-func (d *Deque) PopBack() DequeValue {
+func (d *LinkedDeque[T]) PopBack() (T, bool) {
 	if d.IsEmpty() {
-		return nil
+		var zero T
+		return zero, false
 	}
-	value := d.Back.Value
-	if d.Front == d.Back {
-		d.Front = nil
-		d.Back = nil
+	value := d.back.value
+	if d.front == d.back {
+		d.front = nil
+		d.back = nil
 	} else {
-		d.Back = d.Back.Prev
-		d.Back.Next = nil
+		d.back = d.back.prev
+		d.back.next = nil
 	}
-	d.Size--
-	return value
+	d.size--
+	return value, true
 }
 
 // PeekFront returns the element at the front of the deque without removing it.
-func (d *Deque) PeekFront() DequeValue {
+func (d *LinkedDeque[T]) PeekFront() (T, bool) {
 	if d.IsEmpty() {
-		return nil
+		var zero T
+		return zero, false
 	}
-	return d.Front.Value
+	return d.front.value, true
 }
 
 // PeekBack returns the element at the back of the deque without removing it.
-func (d *Deque) PeekBack() DequeValue {
+func (d *LinkedDeque[T]) PeekBack() (T, bool) {
 	if d.IsEmpty() {
-		return nil
+		var zero T
+		return zero, false
+	}
+	return d.back.value, true
+}
+
+// At returns the element i positions from the front.
+func (d *LinkedDeque[T]) At(i int) (T, error) {
+	node := d.nodeAt(i)
+	if node == nil {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	return node.value, nil
+}
+
+// Set replaces the element i positions from the front.
+func (d *LinkedDeque[T]) Set(i int, value T) error {
+	node := d.nodeAt(i)
+	if node == nil {
+		return ErrIndexOutOfRange
+	}
+	node.value = value
+	return nil
+}
+
+// Rotate moves the last k elements to the front (k > 0) or the first -k elements
+// to the back (k < 0) by relinking the list around its new front node.
+func (d *LinkedDeque[T]) Rotate(k int) {
+	if d.size < 2 {
+		return
+	}
+	k %= d.size
+	if k < 0 {
+		k += d.size
+	}
+	if k == 0 {
+		return
+	}
+	newFront := d.nodeAt(d.size - k)
+	// Close the list into a ring, then cut it just before the new front.
+	d.back.next = d.front
+	d.front.prev = d.back
+	d.front = newFront
+	d.back = newFront.prev
+	d.front.prev = nil
+	d.back.next = nil
+}
+
+// Reverse reverses the order of the elements in place.
+func (d *LinkedDeque[T]) Reverse() {
+	for current := d.front; current != nil; current = current.prev {
+		current.prev, current.next = current.next, current.prev
+	}
+	d.front, d.back = d.back, d.front
+}
+
+// Extend pushes each value onto the back of the deque, in order.
+func (d *LinkedDeque[T]) Extend(values ...T) {
+	for _, value := range values {
+		d.PushBack(value)
 	}
-	return d.Back.Value
+}
+
+// ExtendFront pushes each value onto the front of the deque, in order.
+func (d *LinkedDeque[T]) ExtendFront(values ...T) {
+	for _, value := range values {
+		d.PushFront(value)
+	}
+}
+
+// Clear removes every element from the deque.
+func (d *LinkedDeque[T]) Clear() {
+	d.front = nil
+	d.back = nil
+	d.size = 0
 }
 
 // IsEmpty returns true if the deque is empty, false otherwise.
-func (d *Deque) IsEmpty() bool {
-	return d.Size == 0
+func (d *LinkedDeque[T]) IsEmpty() bool {
+	return d.size == 0
 }
 
 // GetLength returns the number of elements in the deque.
-func (d *Deque) GetLength() int {
-	return d.Size
+func (d *LinkedDeque[T]) GetLength() int {
+	return d.size
+}
+
+// MaxLen returns the deque's bound, or 0 if it is unbounded.
+func (d *LinkedDeque[T]) MaxLen() int {
+	return d.maxLen
+}
+
+func (d *LinkedDeque[T]) isFull() bool {
+	return d.maxLen > 0 && d.size >= d.maxLen
+}
+
+// nodeAt returns the node i positions from the front, walking from whichever
+// end is closer, or nil if i is out of range.
+func (d *LinkedDeque[T]) nodeAt(i int) *DequeNode[T] {
+	if i < 0 || i >= d.size {
+		return nil
+	}
+	if i < d.size/2 {
+		current := d.front
+		for ; i > 0; i-- {
+			current = current.next
+		}
+		return current
+	}
+	current := d.back
+	for j := d.size - 1; j > i; j-- {
+		current = current.prev
+	}
+	return current
+}
+
+// Iterator returns an iterator over the elements from front to back.
+func (d *LinkedDeque[T]) Iterator() Iterator[T] {
+	return d.walk(d.front, func(n *DequeNode[T]) *DequeNode[T] { return n.next })
+}
+
+// ReverseIterator returns an iterator over the elements from back to front.
+func (d *LinkedDeque[T]) ReverseIterator() Iterator[T] {
+	return d.walk(d.back, func(n *DequeNode[T]) *DequeNode[T] { return n.prev })
+}
+
+// walk iterates from start, following advance until it reaches nil.
+func (d *LinkedDeque[T]) walk(start *DequeNode[T], advance func(*DequeNode[T]) *DequeNode[T]) Iterator[T] {
+	current := start
+	return NewIterator(func() (T, bool) {
+		if current == nil {
+			var zero T
+			return zero, false
+		}
+		value := current.value
+		current = advance(current)
+		return value, true
+	})
 }
 
 // Print prints the elements of the deque from front to back.
-func (d *Deque) Print() {
-	current := d.Front
-	for current != nil {
-		fmt.Printf("%v ", current.Value)
-		current = current.Next
+func (d *LinkedDeque[T]) Print() {
+	printDeque[T](d)
+}
+
+// printDeque prints the elements of any deque from front to back.
+func printDeque[T any](d Deque[T]) {
+	for value := range Seq(d.Iterator()) {
+		fmt.Printf("%v ", value)
 	}
 	fmt.Println()
 }
 
-func TestDeque(initDeque []DequeValue) {
-	deque := NewDeque()
-	for _, val := range initDeque {
-		deque.PushBack(val)
+func TestDeque(initDeque []int) {
+	implementations := []struct {
+		name  string
+		deque Deque[int]
+	}{
+		{"ArrayDeque", NewArrayDeque[int]()},
+		{"LinkedDeque", NewLinkedDeque[int]()},
+	}
+	for _, impl := range implementations {
+		fmt.Printf("== %s ==\n", impl.name)
+		testDeque(impl.deque, initDeque)
 	}
+
+	bounded := NewArrayDeque[int](WithMaxLen(3))
+	bounded.Extend(initDeque...)
+	bounded.Extend(1, 2)
+	fmt.Printf("maxlen 3 deque after extend %v + [1 2]: ", initDeque)
+	bounded.Print()
+}
+
+func testDeque(deque Deque[int], initDeque []int) {
+	deque.Extend(initDeque...)
 	fmt.Printf("initial deque: ")
-	deque.Print()
+	printDeque(deque)
 
-	peekerbacker := deque.PeekBack()
+	peekerbacker, _ := deque.PeekBack()
 	fmt.Printf("peek back test -%v-> deque: ", peekerbacker)
-	deque.Print()
+	printDeque(deque)
 
 	//deque.PushBack(90)
 	//fmt.Printf("push back test -> deque: ")
-	//deque.Print()
+	//printDeque(deque)
 	//
 	//deque.PushFront(70)
 	//fmt.Printf("push front test -> deque: ")
-	//deque.Print()
-	//
-	//deque.PushBack(52)
-	//fmt.Printf("push back test -> deque: ")
-	//deque.Print()
+	//printDeque(deque)
 
-	popperbacker := deque.PopBack()
+	popperbacker, _ := deque.PopBack()
 	fmt.Printf("pop back -%v-> deque: ", popperbacker)
-	deque.Print()
+	printDeque(deque)
 
-	//deque.PushFront(37)
-	//fmt.Printf("push front test -> deque: ")
-	//deque.Print()
-	//
-	//popperfronter := deque.PopFront()
-	//fmt.Printf("pop front test -%v-> deque: ", popperfronter)
-	//deque.Print()
+	deque.ExtendFront(37, 38)
+	fmt.Printf("extend front [37 38] -> deque: ")
+	printDeque(deque)
+
+	deque.Rotate(1)
+	fmt.Printf("rotate 1 -> deque: ")
+	printDeque(deque)
+
+	deque.Reverse()
+	fmt.Printf("reverse -> deque: ")
+	printDeque(deque)
+
+	at, _ := deque.At(1)
+	fmt.Printf("at [1] -> %v\n", at)
 
 	fmt.Printf("deque length: %v\n", deque.GetLength())
 	fmt.Printf("deque isEmpty: %v\n", deque.IsEmpty())
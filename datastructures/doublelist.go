@@ -1,37 +1,57 @@
 package datastructures
 
-import "fmt"
+import (
+	"cmp"
+	"fmt"
+)
 
-type DoubleNode struct {
-	data NodeData
-	prev *DoubleNode
-	next *DoubleNode
+type DoubleNode[T any] struct {
+	data T
+	prev *DoubleNode[T]
+	next *DoubleNode[T]
 }
 
-type DoublyLinkedList struct {
-	head *DoubleNode
-	tail *DoubleNode
+// Data returns the value stored in the node.
+func (n *DoubleNode[T]) Data() T {
+	return n.data
+}
+
+type DoublyLinkedList[T any] struct {
+	head *DoubleNode[T]
+	tail *DoubleNode[T]
 	size int
 }
 
 // NewLinkedListDouble creates a new empty doubly linked list
-func NewLinkedListDouble() *DoublyLinkedList {
-	return &DoublyLinkedList{}
+func NewLinkedListDouble[T any]() *DoublyLinkedList[T] {
+	return &DoublyLinkedList[T]{}
 }
 
-func (dll *DoublyLinkedList) Append(data NodeData) {
-	newNode := &DoubleNode{data: data, prev: dll.tail}
-	if dll.size == 0 {
-		dll.head = newNode
-	} else {
-		dll.tail.next = newNode
+// Len returns the number of nodes in the list.
+func (dll *DoublyLinkedList[T]) Len() int {
+	return dll.size
+}
+
+// IsEmpty returns true if the list has no nodes.
+func (dll *DoublyLinkedList[T]) IsEmpty() bool {
+	return dll.size == 0
+}
+
+func (dll *DoublyLinkedList[T]) Append(values ...T) {
+	for _, data := range values {
+		newNode := &DoubleNode[T]{data: data, prev: dll.tail}
+		if dll.size == 0 {
+			dll.head = newNode
+		} else {
+			dll.tail.next = newNode
+		}
+		dll.tail = newNode
+		dll.size++
 	}
-	dll.tail = newNode
-	dll.size++
 }
 
-func (dll *DoublyLinkedList) Prepend(data NodeData) {
-	newNode := &DoubleNode{data: data, next: dll.head}
+func (dll *DoublyLinkedList[T]) Prepend(data T) {
+	newNode := &DoubleNode[T]{data: data, next: dll.head}
 	if dll.size == 0 {
 		dll.tail = newNode
 	} else {
@@ -41,15 +61,55 @@ func (dll *DoublyLinkedList) Prepend(data NodeData) {
 	dll.size++
 }
 
-func (dll *DoublyLinkedList) InsertAfter(target *DoubleNode, data NodeData) bool {
-	if dll.head == nil {
-		dll.head = &DoubleNode{}
-		dll.tail = &DoubleNode{}
+// Get returns the data stored in the node at index.
+func (dll *DoublyLinkedList[T]) Get(index int) (T, error) {
+	node := dll.nodeAt(index)
+	if node == nil {
+		var zero T
+		return zero, ErrIndexOutOfRange
 	}
+	return node.data, nil
+}
+
+// Set replaces the data stored in the node at index.
+func (dll *DoublyLinkedList[T]) Set(index int, data T) error {
+	node := dll.nodeAt(index)
+	if node == nil {
+		return ErrIndexOutOfRange
+	}
+	node.data = data
+	return nil
+}
+
+// Insert places a new node with the given data at index. An index equal to Len appends.
+func (dll *DoublyLinkedList[T]) Insert(index int, data T) error {
+	if index < 0 || index > dll.size {
+		return ErrIndexOutOfRange
+	}
+	if index == 0 {
+		dll.Prepend(data)
+		return nil
+	}
+	dll.InsertAfter(dll.nodeAt(index-1), data)
+	return nil
+}
+
+// RemoveAt removes the node at index and returns its data.
+func (dll *DoublyLinkedList[T]) RemoveAt(index int) (T, error) {
+	node := dll.nodeAt(index)
+	if node == nil {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	dll.Remove(node)
+	return node.data, nil
+}
+
+func (dll *DoublyLinkedList[T]) InsertAfter(target *DoubleNode[T], data T) bool {
 	if target == nil {
 		return false
 	}
-	newNode := &DoubleNode{data: data, prev: target, next: target.next}
+	newNode := &DoubleNode[T]{data: data, prev: target, next: target.next}
 	target.next = newNode
 	if newNode.next != nil {
 		newNode.next.prev = newNode
@@ -60,7 +120,7 @@ func (dll *DoublyLinkedList) InsertAfter(target *DoubleNode, data NodeData) bool
 	return true
 }
 
-func (dll *DoublyLinkedList) Remove(target *DoubleNode) bool {
+func (dll *DoublyLinkedList[T]) Remove(target *DoubleNode[T]) bool {
 	if target == nil || dll.size == 0 {
 		return false
 	}
@@ -74,15 +134,35 @@ func (dll *DoublyLinkedList) Remove(target *DoubleNode) bool {
 	} else {
 		target.next.prev = target.prev
 	}
-	target = nil
 	dll.size--
 	return true
 }
 
-func (dll *DoublyLinkedList) Search(targetData NodeData) *DoubleNode {
+// Clear removes every node from the list.
+func (dll *DoublyLinkedList[T]) Clear() {
+	dll.head = nil
+	dll.tail = nil
+	dll.size = 0
+}
+
+// Search returns the index of the first node whose data compares equal to
+// target, or -1 if no such node exists.
+func (dll *DoublyLinkedList[T]) Search(target T, compare func(a, b T) int) int {
+	index := 0
+	for current := dll.head; current != nil; current = current.next {
+		if compare(current.data, target) == 0 {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// Find returns the first node whose data compares equal to target, or nil.
+func (dll *DoublyLinkedList[T]) Find(target T, compare func(a, b T) int) *DoubleNode[T] {
 	current := dll.head
 	for current != nil {
-		if current.data == targetData {
+		if compare(current.data, target) == 0 {
 			return current // Found the target data, return the node
 		}
 		current = current.next
@@ -90,7 +170,43 @@ func (dll *DoublyLinkedList) Search(targetData NodeData) *DoubleNode {
 	return nil // Target data not found in the list
 }
 
-func (dll *DoublyLinkedList) InsertionSort() {
+// ToSlice returns the data of every node, from head to tail.
+func (dll *DoublyLinkedList[T]) ToSlice() []T {
+	out := make([]T, 0, dll.size)
+	for current := dll.head; current != nil; current = current.next {
+		out = append(out, current.data)
+	}
+	return out
+}
+
+// Iterator returns an iterator over the node data from head to tail.
+func (dll *DoublyLinkedList[T]) Iterator() Iterator[T] {
+	return dll.walk(dll.head, func(n *DoubleNode[T]) *DoubleNode[T] { return n.next })
+}
+
+// ReverseIterator returns an iterator over the node data from tail to head.
+func (dll *DoublyLinkedList[T]) ReverseIterator() Iterator[T] {
+	return dll.walk(dll.tail, func(n *DoubleNode[T]) *DoubleNode[T] { return n.prev })
+}
+
+// walk iterates from start, following advance until it reaches nil.
+func (dll *DoublyLinkedList[T]) walk(start *DoubleNode[T], advance func(*DoubleNode[T]) *DoubleNode[T]) Iterator[T] {
+	current := start
+	return NewIterator(func() (T, bool) {
+		if current == nil {
+			var zero T
+			return zero, false
+		}
+		data := current.data
+		current = advance(current)
+		return data, true
+	})
+}
+
+func (dll *DoublyLinkedList[T]) InsertionSort(compare func(a, b T) int) {
+	if dll.head == nil {
+		return
+	}
 	// Start from the second node (the first node is already sorted).
 	current := dll.head.next
 	for current != nil {
@@ -100,35 +216,52 @@ func (dll *DoublyLinkedList) InsertionSort() {
 		// of the list (before curNode).
 		search := current.prev
 		// Find the correct position by comparing data values.
-		for search != nil && search.data.(int) > current.data.(int) {
+		for search != nil && compare(search.data, current.data) > 0 {
 			search = search.prev
 		}
-		// Remove curNode from its current position in the list.
-		dll.Remove(current)
+		if search != current.prev {
+			// Remove curNode from its current position in the list.
+			dll.Remove(current)
 
-		// If searchNode is null, curNode should become the new head.
-		if search == nil {
-			current.prev = nil
-			dll.Prepend(current)
-		} else {
-			dll.InsertAfter(search, current.data)
+			// If searchNode is null, curNode should become the new head.
+			if search == nil {
+				dll.Prepend(current.data)
+			} else {
+				dll.InsertAfter(search, current.data)
+			}
 		}
 		// Move to the next node in the unsorted part of the list.
 		current = next
 	}
 }
 
-func (dll *DoublyLinkedList) DisplayDouble() {
+// nodeAt returns the node at index, walking from whichever end is closer,
+// or nil if index is out of range.
+func (dll *DoublyLinkedList[T]) nodeAt(index int) *DoubleNode[T] {
+	if index < 0 || index >= dll.size {
+		return nil
+	}
+	if index < dll.size/2 {
+		current := dll.head
+		for i := 0; i < index; i++ {
+			current = current.next
+		}
+		return current
+	}
+	current := dll.tail
+	for i := dll.size - 1; i > index; i-- {
+		current = current.prev
+	}
+	return current
+}
+
+func (dll *DoublyLinkedList[T]) DisplayDouble() {
 	if dll.head == nil {
 		fmt.Println(" [head] -> <nil>")
 		return
 	}
 	current := dll.head
-	if dll.tail.data == nil {
-		fmt.Printf(" [tail] -> <nil>\n")
-	} else {
-		fmt.Printf(" [tail] -> (%d)\n", dll.tail.data)
-	}
+	fmt.Printf(" [tail] -> (%v)\n", dll.tail.data)
 
 	fmt.Printf(" [head] -> ")
 	for current != nil {
@@ -138,19 +271,18 @@ func (dll *DoublyLinkedList) DisplayDouble() {
 	fmt.Println("nil")
 }
 
-func TestDoublyLinkedList(startList []NodeData) {
-	list := NewLinkedListDouble()
+func TestDoublyLinkedList(startList []int) {
+	compare := cmp.Compare[int]
+	list := NewLinkedListDouble[int]()
 	if len(startList) > 0 {
 		fmt.Println("initial list:")
-		for _, data := range startList {
-			list.Append(data)
-		}
+		list.Append(startList...)
 	}
 	list.DisplayDouble()
-	removeTest := []NodeData{96, 59}
+	removeTest := []int{96, 59}
 	for _, remove := range removeTest {
 		fmt.Printf("\nremove test result (%d):\n", remove)
-		list.Remove(list.Search(remove))
+		list.Remove(list.Find(remove, compare))
 		list.DisplayDouble()
 	}
 }
@@ -0,0 +1,188 @@
+package datastructures
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/bits"
+)
+
+// Hasher computes the hash code a HashTable uses to pick a key's bucket.
+// Keys that are equal must hash to the same value.
+type Hasher[K any] interface {
+	Hash(key K) uint64
+}
+
+// HasherFunc adapts an ordinary function to the Hasher interface, e.g. to hash
+// struct keys by combining the hashes of their fields.
+type HasherFunc[K any] func(key K) uint64
+
+// Hash calls f(key).
+func (f HasherFunc[K]) Hash(key K) uint64 {
+	return f(key)
+}
+
+// Integer is the set of integer key types the integer hashers accept.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Bytes is the set of byte-sequence key types the string hashers accept.
+type Bytes interface {
+	~string | ~[]byte
+}
+
+// IdentityHasher hashes an integer key to its own two's-complement bit pattern.
+// It is the fastest hasher and spreads sequential keys perfectly, but keys that
+// share a common factor with the table capacity pile into the same buckets.
+type IdentityHasher[K Integer] struct{}
+
+// Hash returns key converted to uint64.
+func (IdentityHasher[K]) Hash(key K) uint64 {
+	return uint64(key)
+}
+
+const (
+	fnvOffsetBasis = 14695981039346656037
+	fnvPrime       = 1099511628211
+)
+
+// FNV1aHasher hashes strings and byte slices with 64-bit FNV-1a: a fast,
+// well-distributed, but unkeyed hash, so an attacker who can choose the keys can
+// also choose collisions.
+type FNV1aHasher[K Bytes] struct{}
+
+// Hash returns the FNV-1a hash of key.
+func (FNV1aHasher[K]) Hash(key K) uint64 {
+	return fnv1a(key)
+}
+
+// FNV1aIntHasher hashes integer keys with 64-bit FNV-1a over their eight
+// little-endian bytes, mixing every bit of the key into the low bits the table
+// uses to choose a bucket.
+type FNV1aIntHasher[K Integer] struct{}
+
+// Hash returns the FNV-1a hash of key.
+func (FNV1aIntHasher[K]) Hash(key K) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(key))
+	return fnv1a(buf[:])
+}
+
+func fnv1a[B Bytes](data B) uint64 {
+	hash := uint64(fnvOffsetBasis)
+	for i := 0; i < len(data); i++ {
+		hash ^= uint64(data[i])
+		hash *= fnvPrime
+	}
+	return hash
+}
+
+// SipHasher hashes strings and byte slices with SipHash-2-4 under a secret
+// 128-bit seed. Without the seed an attacker cannot predict which keys collide,
+// so it resists hash-flooding attacks on tables filled with untrusted keys.
+type SipHasher[K Bytes] struct {
+	k0, k1 uint64
+}
+
+// NewSipHasher creates a SipHasher with a random seed.
+func NewSipHasher[K Bytes]() SipHasher[K] {
+	k0, k1 := randomSeed()
+	return SipHasher[K]{k0: k0, k1: k1}
+}
+
+// NewSipHasherWithSeed creates a SipHasher with a fixed seed, for hashes that
+// must be reproducible across runs.
+func NewSipHasherWithSeed[K Bytes](k0, k1 uint64) SipHasher[K] {
+	return SipHasher[K]{k0: k0, k1: k1}
+}
+
+// Hash returns the keyed SipHash-2-4 of key.
+func (h SipHasher[K]) Hash(key K) uint64 {
+	return sipHash24(h.k0, h.k1, key)
+}
+
+// SipIntHasher hashes integer keys with SipHash-2-4 over their eight
+// little-endian bytes under a secret seed.
+type SipIntHasher[K Integer] struct {
+	k0, k1 uint64
+}
+
+// NewSipIntHasher creates a SipIntHasher with a random seed.
+func NewSipIntHasher[K Integer]() SipIntHasher[K] {
+	k0, k1 := randomSeed()
+	return SipIntHasher[K]{k0: k0, k1: k1}
+}
+
+// NewSipIntHasherWithSeed creates a SipIntHasher with a fixed seed.
+func NewSipIntHasherWithSeed[K Integer](k0, k1 uint64) SipIntHasher[K] {
+	return SipIntHasher[K]{k0: k0, k1: k1}
+}
+
+// Hash returns the keyed SipHash-2-4 of key.
+func (h SipIntHasher[K]) Hash(key K) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(key))
+	return sipHash24(h.k0, h.k1, buf[:])
+}
+
+// randomSeed draws a 128-bit SipHash key from the operating system's CSPRNG.
+func randomSeed() (uint64, uint64) {
+	var seed [16]byte
+	_, _ = rand.Read(seed[:]) // crypto/rand.Read never returns an error
+	return binary.LittleEndian.Uint64(seed[:8]), binary.LittleEndian.Uint64(seed[8:])
+}
+
+// sipHash24 computes SipHash-2-4 (two compression rounds per 8-byte block, four
+// finalization rounds) of data under the key (k0, k1).
+func sipHash24[B Bytes](k0, k1 uint64, data B) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	round := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+	compress := func(m uint64) {
+		v3 ^= m
+		round()
+		round()
+		v0 ^= m
+	}
+
+	n := len(data)
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		var m uint64
+		for j := 0; j < 8; j++ {
+			m |= uint64(data[i+j]) << (8 * j)
+		}
+		compress(m)
+	}
+	// The final block holds the remaining bytes and the message length in its top byte.
+	last := uint64(n) << 56
+	for j := 0; i+j < n; j++ {
+		last |= uint64(data[i+j]) << (8 * j)
+	}
+	compress(last)
+
+	v2 ^= 0xff
+	for r := 0; r < 4; r++ {
+		round()
+	}
+	return v0 ^ v1 ^ v2 ^ v3
+}
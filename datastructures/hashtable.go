@@ -3,196 +3,374 @@ package datastructures
 import (
 	"fmt"
 	"github.com/ryanuber/columnize"
+	"iter"
 	"strings"
 )
 
-// HashNode represents a key-value pair stored in the hash table.
-type HashNode struct {
-	key   int
-	value HashNodeValue
-	next  *HashNode
+// HashNode represents a key-value pair stored in a chaining hash table.
+type HashNode[K, V any] struct {
+	key   K
+	value V
+	next  *HashNode[K, V]
 }
 
-type HashNodeValue interface{}
+// HashTable represents a hash table that resolves collisions by separate
+// chaining (the default) or by one of several open-addressing strategies; see
+// WithCollisionStrategy.
+//
+// Keys are mapped to buckets by a pluggable Hasher and compared with a pluggable
+// equality function, so any key type can be stored as long as equal keys hash alike.
+//
+// The table grows (doubling) when its load factor rises above a maximum and
+// shrinks (halving, never below its initial capacity) when it falls below a
+// minimum; see WithLoadFactors and WithIncrementalRehash.
+//
+// A HashTable is not safe for concurrent use, except that lookups (HashSearch,
+// HashSearchProbe, Iterator, All and Info) never modify it, so any number of
+// goroutines may look keys up while none is writing.
+type HashTable[K, V any] struct {
+	table      []*HashNode[K, V] // buckets of a chaining table
+	slots      []hashSlot[K, V]  // slots of an open-addressing table
+	capacity   int
+	size       int
+	tombstones int              // slots of removed entries that probes must skip over
+	stash      []hashSlot[K, V] // cuckoo entries that fit in neither of their slots
+	hasher     Hasher[K]
+	equal      func(a, b K) bool
+	hashTableOptions
+	// During an incremental rehash, old holds the table being migrated from and
+	// migrated counts its buckets that have already been moved into table.
+	old      []*HashNode[K, V]
+	migrated int
+}
 
-// HashTable represents a basic hash table with separate chaining.
-type HashTable struct {
-	table    []*HashNode
-	capacity int
-	emptied  []bool // Tracks emptied buckets
+// NewHashTable creates a new hash table with a specified capacity for a
+// comparable key type, using == to compare keys.
+func NewHashTable[K comparable, V any](capacity int, hasher Hasher[K], opts ...HashTableOption) *HashTable[K, V] {
+	return NewHashTableFunc[K, V](capacity, hasher, func(a, b K) bool { return a == b }, opts...)
 }
 
-// NewHashTable creates a new hash table with a specified capacity.
-func NewHashTable(capacity int) *HashTable {
-	return &HashTable{
-		table:    make([]*HashNode, capacity),
-		capacity: capacity,
-		emptied:  make([]bool, capacity),
+// NewHashTableFunc creates a new hash table with a specified capacity that
+// compares keys with equal. Use it for keys that are not comparable with ==
+// (such as byte slices) or that need a looser notion of equality.
+//
+// Open-addressing tables round the capacity up to a power of two (at least 2),
+// which guarantees that every probe sequence visits every slot.
+func NewHashTableFunc[K, V any](capacity int, hasher Hasher[K], equal func(a, b K) bool, opts ...HashTableOption) *HashTable[K, V] {
+	o := newHashTableOptions(opts)
+	capacity = max(capacity, 1)
+	if o.strategy.openAddressing() {
+		capacity = nextPowerOfTwo(max(capacity, 2))
+	}
+	o.minCapacity = capacity
+	ht := &HashTable[K, V]{
+		capacity:         capacity,
+		hasher:           hasher,
+		equal:            equal,
+		hashTableOptions: o,
+	}
+	if o.strategy.openAddressing() {
+		ht.slots = make([]hashSlot[K, V], capacity)
+	} else {
+		ht.table = make([]*HashNode[K, V], capacity)
 	}
+	return ht
+}
+
+// bucket returns the index of the bucket key hashes to. The hash is unsigned,
+// so every key, including negative integers, maps into [0, capacity).
+func (ht *HashTable[K, V]) bucket(key K) int {
+	return bucketIndex(ht.hasher.Hash(key), ht.capacity)
+}
+
+func bucketIndex(hash uint64, capacity int) int {
+	return int(hash % uint64(capacity))
 }
 
-// HashInsert inserts a key-value pair into the hash table.
-func (ht *HashTable) HashInsert(key int, value HashNodeValue) {
-	hash := key % ht.capacity
-	node := &HashNode{key: key, value: value}
+// HashInsert inserts a key-value pair into the hash table, replacing the value
+// if the key is already present.
+func (ht *HashTable[K, V]) HashInsert(key K, value V) {
+	if ht.strategy.openAddressing() {
+		ht.openInsert(key, value)
+		return
+	}
+	ht.rehashStep()
+	if node, _ := ht.searchOld(key, nil); node != nil {
+		node.value = value
+		return
+	}
+	hash := ht.bucket(key)
+	node := &HashNode[K, V]{key: key, value: value}
 
 	if ht.table[hash] == nil {
 		ht.table[hash] = node
 	} else {
 		// Handle collision by chaining.
 		current := ht.table[hash]
-		for current.next != nil {
+		for {
+			if ht.equal(current.key, key) {
+				current.value = value
+				return
+			}
+			if current.next == nil {
+				break
+			}
 			current = current.next
 		}
 		current.next = node
 	}
+	ht.size++
+	ht.growIfNeeded()
 }
 
-// HashInsertLinearProbing inserts a key-value pair into the hash table with linear probing.
-func (ht *HashTable) HashInsertLinearProbing(key int, value HashNodeValue) {
-	index := key % ht.capacity
-
-	// Linear probing until an empty slot is found.
-	for ht.table[index] != nil {
-		index = (index + 1) % ht.capacity
-	}
+// HashSearch searches for a key in the hash table and returns its value (if found).
+// It also returns the number of elements checked during the search: the chained
+// nodes compared, or the slots probed by an open-addressing table.
+func (ht *HashTable[K, V]) HashSearch(key K) (V, bool, int) {
+	return ht.search(key, nil)
+}
 
-	ht.table[index] = &HashNode{key: key, value: value}
+// HashSearchProbe is like HashSearch but returns the indexes of the buckets (or
+// slots) examined, in probe order, instead of a count.
+func (ht *HashTable[K, V]) HashSearchProbe(key K) (V, bool, []int) {
+	var bucketsProbed []int
+	value, found, _ := ht.search(key, func(bucket int) {
+		bucketsProbed = append(bucketsProbed, bucket)
+	})
+	return value, found, bucketsProbed
 }
 
-// HashSearch searches for a key in the hash table and returns its value (if found).
-// It also returns the number of elements checked during the search.
-func (ht *HashTable) HashSearch(key int) (HashNodeValue, bool, int) {
-	hash := key % ht.capacity
+// search looks key up, calling visit (if not nil) with each bucket examined.
+func (ht *HashTable[K, V]) search(key K, visit func(bucket int)) (V, bool, int) {
+	var zero V
+	if ht.strategy.openAddressing() {
+		index, probed := ht.openFind(key, visit)
+		if index < 0 {
+			return zero, false, probed
+		}
+		return ht.slot(index).value, true, probed
+	}
+
+	hash := ht.bucket(key)
+	if visit != nil {
+		visit(hash)
+	}
 	current := ht.table[hash]
 	elementsChecked := 0
 
 	for current != nil {
 		elementsChecked++
-		if current.key == key {
+		if ht.equal(current.key, key) {
 			return current.value, true, elementsChecked
 		}
 		current = current.next
 	}
 
-	return nil, false, elementsChecked
+	node, checked := ht.searchOld(key, visit)
+	elementsChecked += checked
+	if node != nil {
+		return node.value, true, elementsChecked
+	}
+	return zero, false, elementsChecked
 }
 
-func (ht *HashTable) HashSearchLinearProbe(key int) (HashNodeValue, bool, int, []int) {
-	index := key % ht.capacity
-	current := ht.table[index]
-	elementsChecked := 0
-	bucketsProbed := []int{index}
-
-	for (current != nil || !ht.emptied[index]) && (len(bucketsProbed) < ht.capacity) {
-		elementsChecked++
-		if current.key == key {
-			return current.value, true, elementsChecked, bucketsProbed
-		}
-		bucketsProbed = append(bucketsProbed, index) // Record the bucket probed
-		// If the bucket is empty after removal or has been empty since the start, continue probing the next bucket.
-		if ht.emptied[index] {
-			index = (index + 1) % ht.capacity
-			current = ht.table[index]
-		} else {
-			return nil, false, elementsChecked, bucketsProbed
-		}
+// HashRemove removes a key-value pair from the hash table, reporting whether
+// the key was present.
+func (ht *HashTable[K, V]) HashRemove(key K) bool {
+	if ht.strategy.openAddressing() {
+		return ht.openRemove(key)
 	}
-
-	return nil, false, elementsChecked, bucketsProbed
+	ht.rehashStep()
+	if removeFromChain(ht.table, ht.bucket(key), key, ht.equal) ||
+		(ht.old != nil && removeFromChain(ht.old, bucketIndex(ht.hasher.Hash(key), len(ht.old)), key, ht.equal)) {
+		ht.size--
+		ht.shrinkIfNeeded()
+		return true
+	}
+	return false
 }
 
-// HashRemove removes a key-value pair from the hash table.
-func (ht *HashTable) HashRemove(key int) {
-	hash := key % ht.capacity
-	current := ht.table[hash]
-	var prev *HashNode
+// removeFromChain unlinks key from the chain in table[hash], reporting whether it was found.
+func removeFromChain[K, V any](table []*HashNode[K, V], hash int, key K, equal func(a, b K) bool) bool {
+	current := table[hash]
+	var prev *HashNode[K, V]
 
 	for current != nil {
-		if current.key == key {
+		if equal(current.key, key) {
 			if prev == nil {
-				ht.table[hash] = current.next
+				table[hash] = current.next
 			} else {
 				prev.next = current.next
 			}
-			return
+			return true
 		}
 		prev = current
 		current = current.next
 	}
+	return false
 }
 
-// HashRemoveLinearProbe removes a key-value pair from the hash table with linear probing.
-func (ht *HashTable) HashRemoveLinearProbe(key int) {
-	index := key % ht.capacity
-	current := ht.table[index]
-	var prev *HashNode
+// Iterator returns an iterator over the key-value pairs in bucket order.
+// Pairs that share a bucket are produced in chain order, and a cuckoo table's
+// stashed pairs come last.
+func (ht *HashTable[K, V]) Iterator() Iterator[Entry[K, V]] {
+	if ht.strategy.openAddressing() {
+		slot := 0
+		return NewIterator(func() (Entry[K, V], bool) {
+			for ; slot < len(ht.slots)+len(ht.stash); slot++ {
+				if s := ht.slot(slot); s.state == slotOccupied {
+					slot++
+					return Entry[K, V]{Key: s.key, Value: s.value}, true
+				}
+			}
+			return Entry[K, V]{}, false
+		})
+	}
 
-	for current != nil {
-		if current.key == key {
-			if prev == nil {
-				ht.table[index] = current.next
-				ht.emptied[index] = true // Mark the bucket as emptied
-			} else {
-				prev.next = current.next
-				ht.emptied[index] = true // Mark the bucket as emptied
+	// While an incremental rehash is in progress, the not-yet-migrated buckets
+	// of the old table follow the new table's buckets.
+	tables := [][]*HashNode[K, V]{ht.table, ht.old}
+	bucket := 0
+	var current *HashNode[K, V]
+	return NewIterator(func() (Entry[K, V], bool) {
+		for current == nil {
+			for len(tables) > 0 && bucket >= len(tables[0]) {
+				tables, bucket = tables[1:], 0
 			}
-			return
+			if len(tables) == 0 {
+				return Entry[K, V]{}, false
+			}
+			current = tables[0][bucket]
+			bucket++
 		}
-		prev = current
+		entry := Entry[K, V]{Key: current.key, Value: current.value}
 		current = current.next
+		return entry, true
+	})
+}
+
+// All returns an iter.Seq2 over the key-value pairs, for range-over-func.
+func (ht *HashTable[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := range Seq(ht.Iterator()) {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
 	}
 }
 
-// Print prints the contents of the hash table.
-func (ht *HashTable) Print() {
-	result := []string{"\x1fBucket\x1fValues\x1fEmptied\x1f"}
-	for i, node := range ht.table {
-		bucketID := i
-		var keyValues []string
-		for node != nil {
-			keyValues = append(keyValues, fmt.Sprintf("(%v)", node.value))
-			node = node.next
+// Print prints the contents of the hash table. Open-addressing tables also show
+// each slot's state: the probe at which its entry was placed, or a tombstone.
+// If a StatsFormat is given, the table's Stats follow in that format.
+func (ht *HashTable[K, V]) Print(stats ...StatsFormat) {
+	var result []string
+	if ht.strategy.openAddressing() {
+		result = append(result, "\x1fBucket\x1fValues\x1fState\x1f")
+		for i, slot := range ht.slots {
+			value, state := "nil", ""
+			switch slot.state {
+			case slotOccupied:
+				value, state = fmt.Sprintf("(%v)", slot.value), fmt.Sprintf("probe %d", slot.dist)
+			case slotTombstone:
+				state = "tombstone"
+			}
+			result = append(result, fmt.Sprintf("\x1f%d\x1f%s\x1f%s\x1f", i, value, state))
 		}
-		if len(keyValues) == 0 {
-			keyValues = append(keyValues, fmt.Sprintf("nil"))
+		for i, slot := range ht.stash {
+			result = append(result, fmt.Sprintf("\x1f%d\x1f(%v)\x1fstash\x1f", ht.capacity+i, slot.value))
+		}
+	} else {
+		result = append(result, "\x1fBucket\x1fValues\x1f")
+		for i, node := range ht.table {
+			bucketID := i
+			var keyValues []string
+			for node != nil {
+				keyValues = append(keyValues, fmt.Sprintf("(%v)", node.value))
+				node = node.next
+			}
+			if len(keyValues) == 0 {
+				keyValues = append(keyValues, fmt.Sprintf("nil"))
+			}
+			bucketValues := strings.Join(keyValues, " -> ")
+			result = append(result, fmt.Sprintf("\x1f%d\x1f%s\x1f", bucketID, bucketValues))
 		}
-		bucketValues := strings.Join(keyValues, " -> ")
-		result = append(result, fmt.Sprintf("\x1f%d\x1f%s\x1f%v\x1f", bucketID, bucketValues, ht.emptied[i]))
 	}
 	output := columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f}), Glue: " "})
 	fmt.Println(output)
+	if ht.old != nil {
+		fmt.Printf("rehashing: %d of %d old buckets migrated\n", ht.migrated, len(ht.old))
+	}
+	for _, format := range stats {
+		ht.printStats(format)
+	}
 }
 
 func TestHashTable() {
-	ht := NewHashTable(10)
+	ht := NewHashTable[int, int](8, IdentityHasher[int]{}, WithCollisionStrategy(LinearProbing))
 	values := []int{20, 12, 95, 47, 57}
 	for _, v := range values {
-		ht.HashInsertLinearProbing(v, v)
+		ht.HashInsert(v, v)
 	}
-	ht.emptied[1] = true
-	ht.emptied[4] = true
-	ht.emptied[8] = true
 	fmt.Println("initial: ")
 	ht.Print()
 
-	ht.HashRemoveLinearProbe(95)
-	fmt.Println("HashRemoveLinearProbe (95): ")
-	ht.Print()
+	ht.HashRemove(95)
+	fmt.Println("HashRemove (95): ")
+	ht.Print(StatsTable)
 	//test := []int{13, 37}
 	//for _, val := range test {
-	//	ht.HashInsertLinearProbing(val, val)
+	//	ht.HashInsert(val, val)
 	//}
 	//ht.Print()
 
-	// Perform searches and count comparisons.
-	searchItems := []int{95, 57}
+	// Perform searches and count comparisons. Finding 47 probes past the
+	// tombstone that removing 95 left in its home slot.
+	searchItems := []int{95, 47, 57}
 	for _, searchKey := range searchItems {
-		value, found, elements, buckets := ht.HashSearchLinearProbe(searchKey)
+		value, found, buckets := ht.HashSearchProbe(searchKey)
 		if found {
-			fmt.Printf("(found) Key (%d) -> (%d) Value. Comparisons: %d | Buckets: %d\n", searchKey, value, elements, buckets)
+			fmt.Printf("(found) Key (%d) -> (%d) Value. Comparisons: %d | Buckets: %d\n", searchKey, value, len(buckets), buckets)
+		} else {
+			fmt.Printf("(not found) Key (%d) -> (%v) returned. Comparisons: %d | Buckets: %d\n", searchKey, value, len(buckets), buckets)
+		}
+	}
+
+	// Every collision strategy must behave exactly like a map.
+	for _, strategy := range CollisionStrategies() {
+		if err := CheckHashTable(WithCollisionStrategy(strategy)); err != nil {
+			fmt.Printf("%s: FAIL\n%v\n", strategy, err)
 		} else {
-			fmt.Printf("(not found) Key (%d) -> (%v) returned. Comparisons: %d | Buckets: %d\n", searchKey, value, elements, buckets)
+			fmt.Printf("%s: ok\n", strategy)
 		}
 	}
+
+	// Negative integer keys and string keys hash into range with the built-in hashers.
+	chained := NewHashTable[int, string](7, FNV1aIntHasher[int]{})
+	for _, key := range []int{-3, -10, 4, 11} {
+		chained.HashInsert(key, fmt.Sprintf("v%d", key))
+	}
+	fmt.Println("chaining, negative int keys (FNV-1a): ")
+	chained.Print(StatsJSON)
+
+	// Growing past the maximum load factor doubles the table, migrating two old
+	// buckets per operation; removing most keys shrinks it back.
+	growing := NewHashTable[int, int](4, IdentityHasher[int]{}, WithIncrementalRehash(2))
+	for key := 0; key < 12; key++ {
+		growing.HashInsert(key, key)
+	}
+	info := growing.Info()
+	fmt.Printf("after 12 inserts: capacity %d | size %d | load %.2f | rehashing %v\n", info.Capacity, info.Size, info.LoadFactor, info.Rehashing)
+	for key := 0; key < 11; key++ {
+		growing.HashRemove(key)
+	}
+	info = growing.Info()
+	fmt.Printf("after 11 removes: capacity %d | size %d | load %.2f | rehashing %v\n", info.Capacity, info.Size, info.LoadFactor, info.Rehashing)
+
+	words := NewHashTable[string, int](5, NewSipHasher[string]())
+	for i, word := range []string{"alpha", "beta", "gamma", "delta"} {
+		words.HashInsert(word, i)
+	}
+	value, found, elements := words.HashSearch("gamma")
+	fmt.Printf("string keys (SipHash) search gamma -> %v (found: %v, comparisons: %d)\n", value, found, elements)
 }
@@ -0,0 +1,194 @@
+package datastructures
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+)
+
+// hashTableCheckOps is the number of random operations CheckHashTable replays
+// against both the table under test and a Go map.
+const hashTableCheckOps = 5000
+
+// CheckHashTable runs the HashTable suite against tables built with opts, such
+// as a WithCollisionStrategy option. Every strategy must give the same results
+// as a map on the same operations. It returns nil if the table behaves
+// correctly, otherwise an error describing every violation found.
+func CheckHashTable(opts ...HashTableOption) error {
+	checks := []struct {
+		name string
+		run  func(opts []HashTableOption) error
+	}{
+		{"collisions", checkHashTableCollisions},
+		{"update", checkHashTableUpdate},
+		{"remove-reinsert", checkHashTableRemoveReinsert},
+		{"grow-shrink", checkHashTableGrowShrink},
+		{"colliding-hasher", checkHashTableCollidingHasher},
+		{"random-model", checkHashTableRandomModel},
+	}
+	var errs []error
+	for _, check := range checks {
+		if err := check.run(opts); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", check.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expectTable verifies that ht holds exactly the pairs in want, through search,
+// iteration and size.
+func expectTable(ht *HashTable[int, int], want map[int]int) error {
+	if ht.Info().Size != len(want) {
+		return fmt.Errorf("Size = %d, want %d", ht.Info().Size, len(want))
+	}
+	for key, w := range want {
+		if got, found, _ := ht.HashSearch(key); !found || got != w {
+			return fmt.Errorf("HashSearch(%d) = %d, %v, want %d, true", key, got, found, w)
+		}
+	}
+	got := make(map[int]int, len(want))
+	for key, value := range ht.All() {
+		if _, dup := got[key]; dup {
+			return fmt.Errorf("All() yielded key %d twice", key)
+		}
+		got[key] = value
+	}
+	if !maps.Equal(got, want) {
+		return fmt.Errorf("All() = %v, want %v", got, want)
+	}
+	return nil
+}
+
+// checkHashTableCollisions stores keys that all share a home bucket (with the
+// identity hasher, multiples of the capacity), including negative keys.
+func checkHashTableCollisions(opts []HashTableOption) error {
+	ht := NewHashTable[int, int](16, IdentityHasher[int]{}, opts...)
+	want := map[int]int{}
+	for i := -4; i < 8; i++ {
+		ht.HashInsert(i*16, i)
+		want[i*16] = i
+	}
+	if _, found, _ := ht.HashSearch(8 * 16); found {
+		return fmt.Errorf("HashSearch(%d) found a key never inserted", 8*16)
+	}
+	return expectTable(ht, want)
+}
+
+func checkHashTableUpdate(opts []HashTableOption) error {
+	ht := NewHashTable[int, int](8, IdentityHasher[int]{}, opts...)
+	want := map[int]int{}
+	for i := 0; i < 5; i++ {
+		ht.HashInsert(i*8, i)
+		want[i*8] = i
+	}
+	for i := 0; i < 5; i++ {
+		ht.HashInsert(i*8, -i)
+		want[i*8] = -i
+	}
+	return expectTable(ht, want)
+}
+
+// checkHashTableRemoveReinsert removes keys from the middle of a collision run,
+// which must not hide the keys placed after them, then reinserts them.
+func checkHashTableRemoveReinsert(opts []HashTableOption) error {
+	ht := NewHashTable[int, int](16, IdentityHasher[int]{}, opts...)
+	want := map[int]int{}
+	for i := 0; i < 8; i++ {
+		ht.HashInsert(i*16+3, i)
+		want[i*16+3] = i
+	}
+	for _, i := range []int{0, 3, 4} {
+		if !ht.HashRemove(i*16 + 3) {
+			return fmt.Errorf("HashRemove(%d) = false, want true", i*16+3)
+		}
+		delete(want, i*16+3)
+	}
+	if ht.HashRemove(3) {
+		return fmt.Errorf("HashRemove(3) of a removed key = true, want false")
+	}
+	if err := expectTable(ht, want); err != nil {
+		return err
+	}
+	for _, i := range []int{4, 0} {
+		ht.HashInsert(i*16+3, 100+i)
+		want[i*16+3] = 100 + i
+	}
+	return expectTable(ht, want)
+}
+
+func checkHashTableGrowShrink(opts []HashTableOption) error {
+	ht := NewHashTable[int, int](4, FNV1aIntHasher[int]{}, opts...)
+	want := map[int]int{}
+	for key := 0; key < 1000; key++ {
+		ht.HashInsert(key, key*key)
+		want[key] = key * key
+	}
+	if err := expectTable(ht, want); err != nil {
+		return err
+	}
+	grown := ht.Info().Capacity
+	for key := 0; key < 990; key++ {
+		ht.HashRemove(key)
+		delete(want, key)
+	}
+	if info := ht.Info(); ht.minLoadFactor > 0 && info.Capacity >= grown {
+		return fmt.Errorf("capacity %d after removing 990 of 1000 keys, want < %d", info.Capacity, grown)
+	}
+	return expectTable(ht, want)
+}
+
+// checkHashTableCollidingHasher stores keys that all have the same hash, not
+// just the same home bucket, so no capacity can separate them.
+func checkHashTableCollidingHasher(opts []HashTableOption) error {
+	ht := NewHashTable[int, int](4, HasherFunc[int](func(int) uint64 { return 0 }), opts...)
+	want := map[int]int{}
+	for key := 0; key < 40; key++ {
+		ht.HashInsert(key, -key)
+		want[key] = -key
+	}
+	if err := expectTable(ht, want); err != nil {
+		return err
+	}
+	for key := 0; key < 40; key += 3 {
+		if !ht.HashRemove(key) {
+			return fmt.Errorf("HashRemove(%d) = false, want true", key)
+		}
+		delete(want, key)
+	}
+	if _, found, _ := ht.HashSearch(40); found {
+		return fmt.Errorf("HashSearch(40) found a key never inserted")
+	}
+	return expectTable(ht, want)
+}
+
+// checkHashTableRandomModel replays a fixed pseudo-random mix of inserts,
+// removes and searches over a small key space on the table and on a map.
+func checkHashTableRandomModel(opts []HashTableOption) error {
+	rng := rand.New(rand.NewSource(1))
+	ht := NewHashTable[int, int](2, FNV1aIntHasher[int]{}, opts...)
+	model := map[int]int{}
+	for op := 0; op < hashTableCheckOps; op++ {
+		key := rng.Intn(200) - 100
+		switch rng.Intn(3) {
+		case 0:
+			ht.HashInsert(key, op)
+			model[key] = op
+		case 1:
+			_, want := model[key]
+			if got := ht.HashRemove(key); got != want {
+				return fmt.Errorf("op %d HashRemove(%d) = %v, want %v", op, key, got, want)
+			}
+			delete(model, key)
+		case 2:
+			want, wantFound := model[key]
+			if got, found, _ := ht.HashSearch(key); found != wantFound || got != want {
+				return fmt.Errorf("op %d HashSearch(%d) = %d, %v, want %d, %v", op, key, got, found, want, wantFound)
+			}
+		}
+		if size := ht.Info().Size; size != len(model) {
+			return fmt.Errorf("after op %d: Size = %d, want %d", op, size, len(model))
+		}
+	}
+	return expectTable(ht, model)
+}
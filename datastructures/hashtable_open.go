@@ -0,0 +1,389 @@
+package datastructures
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// CollisionStrategy selects how a HashTable resolves keys that hash to the same bucket.
+type CollisionStrategy int
+
+const (
+	// Chaining keeps a linked list of entries in each bucket.
+	Chaining CollisionStrategy = iota
+	// LinearProbing tries slots h, h+1, h+2, ...
+	LinearProbing
+	// QuadraticProbing tries slots h, h+1, h+3, h+6, ... (triangular offsets),
+	// which breaks up the runs of occupied slots linear probing builds.
+	QuadraticProbing
+	// DoubleHashing tries slots h, h+s, h+2s, ... with a step s derived from a
+	// second hash, so keys that share a home slot follow different sequences.
+	DoubleHashing
+	// RobinHood probes linearly but lets an entry take the slot of a resident that
+	// is closer to its own home slot, keeping probe lengths short and even. Lookups
+	// stop as soon as they pass where the key would have been placed, and removals
+	// shift later entries back instead of leaving tombstones.
+	RobinHood
+	// Cuckoo gives every key two candidate slots and evicts the resident of a
+	// full one to its alternative. The few keys that fit in neither wait in a
+	// small stash, so a lookup probes two slots and then the stash.
+	Cuckoo
+)
+
+const (
+	// maxCuckooKicks is the number of evictions a cuckoo insert attempts before
+	// it gives up and stashes the entry left without a slot.
+	maxCuckooKicks = 32
+	// maxCuckooStash is the number of entries a cuckoo table keeps in its stash
+	// before a failed insert grows the table instead.
+	maxCuckooStash = 4
+	// maxCuckooGrowth bounds how far a cuckoo table grows to empty its stash: to
+	// at most 2^maxCuckooGrowth times the capacity its load needs. Keys whose
+	// hashes are equal share both nests, so no capacity fits more than two of
+	// them; past the bound, the rest stay in the stash, however many there are.
+	maxCuckooGrowth = 3
+)
+
+var collisionStrategyNames = map[CollisionStrategy]string{
+	Chaining:         "chaining",
+	LinearProbing:    "linear probing",
+	QuadraticProbing: "quadratic probing",
+	DoubleHashing:    "double hashing",
+	RobinHood:        "robin hood",
+	Cuckoo:           "cuckoo",
+}
+
+func (s CollisionStrategy) String() string {
+	if name, ok := collisionStrategyNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// CollisionStrategies returns every supported strategy, chaining first.
+func CollisionStrategies() []CollisionStrategy {
+	return []CollisionStrategy{Chaining, LinearProbing, QuadraticProbing, DoubleHashing, RobinHood, Cuckoo}
+}
+
+func (s CollisionStrategy) openAddressing() bool {
+	return s != Chaining
+}
+
+// usesTombstones reports whether removals under s mark slots deleted rather
+// than emptying them. Robin Hood shifts entries back and cuckoo lookups never
+// probe past a slot, so neither needs tombstones.
+func (s CollisionStrategy) usesTombstones() bool {
+	return s == LinearProbing || s == QuadraticProbing || s == DoubleHashing
+}
+
+// WithCollisionStrategy selects how the table resolves collisions. The default
+// is Chaining; every other strategy stores entries directly in a slot array.
+func WithCollisionStrategy(strategy CollisionStrategy) HashTableOption {
+	return func(o *hashTableOptions) {
+		o.strategy = strategy
+	}
+}
+
+type slotState uint8
+
+const (
+	slotEmpty slotState = iota
+	slotOccupied
+	slotTombstone
+)
+
+// hashSlot is one slot of an open-addressing table. dist is the probe number at
+// which the entry was placed (for cuckoo, which of its two slots it occupies).
+type hashSlot[K, V any] struct {
+	key   K
+	value V
+	hash  uint64
+	dist  int
+	state slotState
+}
+
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(n-1))
+}
+
+// mixHash is the splitmix64 finalizer. It derives a second, independent hash
+// for double hashing and cuckoo hashing from the key's hash.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// probe returns the i-th slot in hash's probe sequence. Capacity is a power of
+// two, so the triangular offsets of quadratic probing and the odd step of
+// double hashing both reach every slot.
+func (ht *HashTable[K, V]) probe(hash uint64, i int) int {
+	mask := uint64(ht.capacity - 1)
+	step := uint64(i)
+	switch ht.strategy {
+	case QuadraticProbing:
+		step = uint64(i) * uint64(i+1) / 2
+	case DoubleHashing:
+		step = uint64(i) * (mixHash(hash) | 1)
+	}
+	return int((hash + step) & mask)
+}
+
+// cuckooNests returns the two slots a key with the given hash may occupy.
+func (ht *HashTable[K, V]) cuckooNests(hash uint64) [2]int {
+	mask := uint64(ht.capacity - 1)
+	first := int(hash & mask)
+	second := int(mixHash(hash) & mask)
+	if second == first {
+		second = first ^ 1
+	}
+	return [2]int{first, second}
+}
+
+// openFind returns the slot holding key, or -1, and the number of slots probed.
+// The cuckoo stash's entries are numbered as slots past the end of the slot
+// array; see slot.
+func (ht *HashTable[K, V]) openFind(key K, visit func(bucket int)) (int, int) {
+	hash := ht.hasher.Hash(key)
+	matches := func(index int) bool {
+		if visit != nil {
+			visit(index)
+		}
+		s := &ht.slots[index]
+		return s.state == slotOccupied && s.hash == hash && ht.equal(s.key, key)
+	}
+
+	if ht.strategy == Cuckoo {
+		nests := ht.cuckooNests(hash)
+		for n, index := range nests {
+			if matches(index) {
+				return index, n + 1
+			}
+		}
+		for i, s := range ht.stash {
+			if visit != nil {
+				visit(ht.capacity + i)
+			}
+			if s.hash == hash && ht.equal(s.key, key) {
+				return ht.capacity + i, len(nests) + i + 1
+			}
+		}
+		return -1, len(nests) + len(ht.stash)
+	}
+
+	for i := 0; i < ht.capacity; i++ {
+		index := ht.probe(hash, i)
+		if matches(index) {
+			return index, i + 1
+		}
+		s := &ht.slots[index]
+		// An empty slot ends every probe sequence. Under Robin Hood, so does a
+		// resident closer to home than the key would be: the key would have
+		// displaced it on insertion.
+		if s.state == slotEmpty || (ht.strategy == RobinHood && s.state == slotOccupied && s.dist < i) {
+			return -1, i + 1
+		}
+	}
+	return -1, ht.capacity
+}
+
+// slot returns the slot openFind numbered index: one of the slot array, or,
+// past its end, an entry of the cuckoo stash.
+func (ht *HashTable[K, V]) slot(index int) *hashSlot[K, V] {
+	if index >= ht.capacity {
+		return &ht.stash[index-ht.capacity]
+	}
+	return &ht.slots[index]
+}
+
+// openInsert inserts or updates key in an open-addressing table.
+func (ht *HashTable[K, V]) openInsert(key K, value V) {
+	if index, _ := ht.openFind(key, nil); index >= 0 {
+		ht.slot(index).value = value
+		return
+	}
+	ht.makeRoom()
+	ht.size++
+	entry := hashSlot[K, V]{key: key, value: value, hash: ht.hasher.Hash(key)}
+	leftover, ok := ht.place(entry)
+	if ok {
+		return
+	}
+	// A cuckoo table stashes the entry left without a slot rather than grow
+	// while its stash has room, and for good once it has grown as far as
+	// maxCuckooGrowth allows.
+	if ht.strategy == Cuckoo && (len(ht.stash) < maxCuckooStash || ht.capacity >= ht.maxCuckooCapacity(ht.size)) {
+		ht.stash = append(ht.stash, leftover)
+		return
+	}
+	ht.rebuildSlots(ht.capacity*2, leftover)
+}
+
+// openRemove removes key from an open-addressing table, reporting whether it was present.
+func (ht *HashTable[K, V]) openRemove(key K) bool {
+	index, _ := ht.openFind(key, nil)
+	if index < 0 {
+		return false
+	}
+	switch {
+	case index >= ht.capacity:
+		ht.stash = slices.Delete(ht.stash, index-ht.capacity, index-ht.capacity+1)
+	case ht.strategy.usesTombstones():
+		ht.slots[index] = hashSlot[K, V]{state: slotTombstone}
+		ht.tombstones++
+	case ht.strategy == RobinHood:
+		// Backward-shift deletion: pull each following displaced entry one slot
+		// closer to home until reaching an empty slot or an entry already at home.
+		for {
+			next := (index + 1) & (ht.capacity - 1)
+			if ht.slots[next].state != slotOccupied || ht.slots[next].dist == 0 {
+				ht.slots[index] = hashSlot[K, V]{}
+				break
+			}
+			ht.slots[index] = ht.slots[next]
+			ht.slots[index].dist--
+			index = next
+		}
+	default:
+		ht.slots[index] = hashSlot[K, V]{}
+	}
+	ht.size--
+	ht.shrinkIfNeeded()
+	return true
+}
+
+// openLimit is the number of live entries (plus tombstones) an open-addressing
+// table of the given capacity may hold. It always leaves a slot empty, so that
+// unsuccessful probes terminate, and keeps a cuckoo table at most half full.
+func (ht *HashTable[K, V]) openLimit(capacity int) int {
+	limit := capacity - 1
+	if ht.strategy == Cuckoo {
+		limit = capacity / 2
+	}
+	if ht.maxLoadFactor > 0 {
+		limit = min(limit, int(ht.maxLoadFactor*float64(capacity)))
+	}
+	return limit
+}
+
+// makeRoom ensures the table can take one more entry, growing it if the load
+// would rise too high, or rehashing it in place to clear out tombstones if they
+// are what fill it.
+func (ht *HashTable[K, V]) makeRoom() {
+	capacity := ht.capacity
+	for ht.size+1 > ht.openLimit(capacity) {
+		capacity *= 2
+	}
+	if capacity != ht.capacity || ht.size+1+ht.tombstones > ht.openLimit(capacity) {
+		ht.rebuildSlots(capacity)
+	}
+}
+
+// maxCuckooCapacity is the largest capacity a cuckoo table holding n entries
+// grows to: 2^maxCuckooGrowth times the smallest capacity they fit in.
+func (ht *HashTable[K, V]) maxCuckooCapacity(n int) int {
+	capacity := ht.minCapacity
+	for ht.openLimit(capacity) < n {
+		capacity *= 2
+	}
+	return capacity << maxCuckooGrowth
+}
+
+// place stores entry, which must not already be in the table. Only a cuckoo
+// insert can fail; it then returns the entry left without a slot.
+func (ht *HashTable[K, V]) place(entry hashSlot[K, V]) (hashSlot[K, V], bool) {
+	entry.state = slotOccupied
+	switch ht.strategy {
+	case Cuckoo:
+		from := -1
+		for kick := 0; kick <= maxCuckooKicks; kick++ {
+			nests := ht.cuckooNests(entry.hash)
+			for n, index := range nests {
+				if ht.slots[index].state != slotOccupied {
+					entry.dist = n
+					ht.slots[index] = entry
+					return entry, true
+				}
+			}
+			// Both nests are taken: evict the resident of the one the entry was
+			// not just evicted from, and place the evicted entry next.
+			n := 0
+			if nests[0] == from {
+				n = 1
+			}
+			entry.dist = n
+			entry, ht.slots[nests[n]] = ht.slots[nests[n]], entry
+			from = nests[n]
+		}
+		return entry, false
+	case RobinHood:
+		entry.dist = 0
+		index := int(entry.hash & uint64(ht.capacity-1))
+		for range ht.capacity {
+			resident := &ht.slots[index]
+			if resident.state != slotOccupied {
+				*resident = entry
+				return entry, true
+			}
+			if resident.dist < entry.dist {
+				entry, *resident = *resident, entry
+			}
+			entry.dist++
+			index = (index + 1) & (ht.capacity - 1)
+		}
+		return entry, false
+	default:
+		for i := 0; i < ht.capacity; i++ {
+			index := ht.probe(entry.hash, i)
+			if ht.slots[index].state != slotOccupied {
+				if ht.slots[index].state == slotTombstone {
+					ht.tombstones--
+				}
+				entry.dist = i
+				ht.slots[index] = entry
+				return entry, true
+			}
+		}
+		return entry, false
+	}
+}
+
+// rebuildSlots re-places every entry, stashed ones included, plus any extra
+// ones, into a fresh slot array of newCapacity slots, dropping all tombstones.
+// If a cuckoo table leaves more entries than fit in its stash, it doubles the
+// capacity and tries again, up to maxCuckooCapacity; the entries still left
+// over then all go in the stash.
+func (ht *HashTable[K, V]) rebuildSlots(newCapacity int, extra ...hashSlot[K, V]) {
+	entries := make([]hashSlot[K, V], 0, ht.size)
+	for _, slot := range ht.slots {
+		if slot.state == slotOccupied {
+			entries = append(entries, slot)
+		}
+	}
+	entries = append(append(entries, ht.stash...), extra...)
+	limit := newCapacity
+	if ht.strategy == Cuckoo {
+		limit = max(limit, ht.maxCuckooCapacity(len(entries)))
+	}
+	for {
+		ht.slots = make([]hashSlot[K, V], newCapacity)
+		ht.capacity = newCapacity
+		ht.tombstones = 0
+		ht.stash = nil
+		for _, entry := range entries {
+			if leftover, ok := ht.place(entry); !ok {
+				ht.stash = append(ht.stash, leftover)
+			}
+		}
+		if len(ht.stash) <= maxCuckooStash || newCapacity >= limit {
+			return
+		}
+		newCapacity *= 2
+	}
+}
@@ -0,0 +1,193 @@
+package datastructures
+
+const (
+	defaultMaxLoadFactor = 0.75
+	defaultMinLoadFactor = 0.125
+)
+
+// HashTableOption configures a HashTable created by NewHashTable or NewHashTableFunc.
+type HashTableOption func(*hashTableOptions)
+
+type hashTableOptions struct {
+	maxLoadFactor  float64 // grow when size/capacity rises above this; <= 0 disables growth
+	minLoadFactor  float64 // shrink when size/capacity falls below this; <= 0 disables shrinking
+	minCapacity    int     // the table never shrinks below its initial capacity
+	bucketsPerStep int     // old buckets migrated per operation; 0 rehashes all at once
+	strategy       CollisionStrategy
+}
+
+func newHashTableOptions(opts []HashTableOption) hashTableOptions {
+	o := hashTableOptions{
+		maxLoadFactor: defaultMaxLoadFactor,
+		minLoadFactor: defaultMinLoadFactor,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLoadFactors sets the load factors (size / capacity) that trigger a resize:
+// the table doubles when the load rises above maxLoad and halves when it falls
+// below minLoad. A value <= 0 disables that direction of resizing. minLoad is
+// capped at a quarter of maxLoad so that a table that has just grown or shrunk
+// is never immediately resized back.
+func WithLoadFactors(maxLoad, minLoad float64) HashTableOption {
+	return func(o *hashTableOptions) {
+		o.maxLoadFactor = maxLoad
+		o.minLoadFactor = minLoad
+		if maxLoad > 0 {
+			o.minLoadFactor = min(minLoad, maxLoad/4)
+		}
+	}
+}
+
+// WithIncrementalRehash spreads each resize of a chaining table over later
+// operations: instead of moving every entry at once, each insert and remove
+// migrates bucketsPerOp buckets from the old table, so no single operation pays
+// for the whole rehash. Lookups only read, consulting both tables while the
+// migration is in progress. Open-addressing tables always rehash at once.
+func WithIncrementalRehash(bucketsPerOp int) HashTableOption {
+	return func(o *hashTableOptions) {
+		o.bucketsPerStep = max(bucketsPerOp, 0)
+	}
+}
+
+// HashTableInfo describes a hash table's current size and shape.
+type HashTableInfo struct {
+	Strategy   CollisionStrategy
+	Capacity   int     // number of buckets in the current table
+	Size       int     // number of key-value pairs stored
+	Tombstones int     // slots left behind by removals from an open-addressing table
+	Stashed    int     // entries of a cuckoo table that fit in neither of their slots
+	LoadFactor float64 // Size / Capacity
+	Rehashing  bool    // an incremental rehash is in progress
+	// OldCapacity and Migrated describe an in-progress incremental rehash: the
+	// size of the table being migrated from and how many of its buckets have moved.
+	OldCapacity int
+	Migrated    int
+}
+
+// Info reports the table's capacity, size and load factor.
+func (ht *HashTable[K, V]) Info() HashTableInfo {
+	return HashTableInfo{
+		Strategy:    ht.strategy,
+		Capacity:    ht.capacity,
+		Size:        ht.size,
+		Tombstones:  ht.tombstones,
+		Stashed:     len(ht.stash),
+		LoadFactor:  float64(ht.size) / float64(ht.capacity),
+		Rehashing:   ht.old != nil,
+		OldCapacity: len(ht.old),
+		Migrated:    ht.migrated,
+	}
+}
+
+// growIfNeeded doubles the table once its load factor exceeds the maximum.
+func (ht *HashTable[K, V]) growIfNeeded() {
+	if ht.maxLoadFactor > 0 && float64(ht.size) > ht.maxLoadFactor*float64(ht.capacity) {
+		ht.startRehash(ht.capacity * 2)
+	}
+}
+
+// shrinkIfNeeded halves the table once its load factor drops below the minimum.
+func (ht *HashTable[K, V]) shrinkIfNeeded() {
+	if ht.minLoadFactor > 0 && ht.capacity > ht.minCapacity &&
+		float64(ht.size) < ht.minLoadFactor*float64(ht.capacity) {
+		ht.startRehash(max(ht.capacity/2, ht.minCapacity))
+	}
+}
+
+// startRehash moves the table to newCapacity buckets, either all at once or,
+// for a chaining table with incremental rehashing enabled, a few buckets at a time.
+func (ht *HashTable[K, V]) startRehash(newCapacity int) {
+	if ht.bucketsPerStep == 0 || ht.strategy.openAddressing() {
+		ht.resize(newCapacity)
+		return
+	}
+	ht.finishRehash()
+	ht.old = ht.table
+	ht.migrated = 0
+	ht.table = make([]*HashNode[K, V], newCapacity)
+	ht.capacity = newCapacity
+}
+
+// rehashStep migrates the next few buckets of an in-progress incremental rehash.
+func (ht *HashTable[K, V]) rehashStep() {
+	if ht.old == nil {
+		return
+	}
+	for i := 0; i < ht.bucketsPerStep && ht.migrated < len(ht.old); i++ {
+		ht.migrateBucket(ht.migrated)
+		ht.migrated++
+	}
+	if ht.migrated == len(ht.old) {
+		ht.old = nil
+		ht.migrated = 0
+	}
+}
+
+// finishRehash completes any in-progress incremental rehash.
+func (ht *HashTable[K, V]) finishRehash() {
+	for ht.old != nil {
+		for ; ht.migrated < len(ht.old); ht.migrated++ {
+			ht.migrateBucket(ht.migrated)
+		}
+		ht.old = nil
+		ht.migrated = 0
+	}
+}
+
+// migrateBucket moves every node chained in old[i] into the current table.
+func (ht *HashTable[K, V]) migrateBucket(i int) {
+	for node := ht.old[i]; node != nil; {
+		next := node.next
+		hash := ht.bucket(node.key)
+		node.next = ht.table[hash]
+		ht.table[hash] = node
+		node = next
+	}
+	ht.old[i] = nil
+}
+
+// searchOld looks key up in the not-yet-migrated part of an in-progress
+// incremental rehash, returning its node (or nil) and the number of nodes
+// checked. visit, if not nil, is called with the old bucket's index.
+func (ht *HashTable[K, V]) searchOld(key K, visit func(bucket int)) (*HashNode[K, V], int) {
+	if ht.old == nil {
+		return nil, 0
+	}
+	hash := bucketIndex(ht.hasher.Hash(key), len(ht.old))
+	if visit != nil {
+		visit(hash)
+	}
+	checked := 0
+	for node := ht.old[hash]; node != nil; node = node.next {
+		checked++
+		if ht.equal(node.key, key) {
+			return node, checked
+		}
+	}
+	return nil, checked
+}
+
+// resize rehashes every entry into a new table of newCapacity buckets at once.
+func (ht *HashTable[K, V]) resize(newCapacity int) {
+	if ht.strategy.openAddressing() {
+		ht.rebuildSlots(newCapacity)
+		return
+	}
+	ht.finishRehash()
+	oldTable := ht.table
+	ht.table = make([]*HashNode[K, V], newCapacity)
+	ht.capacity = newCapacity
+	for _, node := range oldTable {
+		for node != nil {
+			next := node.next
+			hash := ht.bucket(node.key)
+			node.next = ht.table[hash]
+			ht.table[hash] = node
+			node = next
+		}
+	}
+}
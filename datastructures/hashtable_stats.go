@@ -0,0 +1,198 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ryanuber/columnize"
+	"math"
+	"slices"
+	"strings"
+)
+
+// HashTableStats describes how evenly a hash table's keys are spread, to help
+// spot a poor Hasher for the keys being stored.
+type HashTableStats struct {
+	Strategy   CollisionStrategy `json:"strategy"`
+	Capacity   int               `json:"capacity"`
+	Size       int               `json:"size"`
+	LoadFactor float64           `json:"load_factor"`
+	// EmptyBuckets counts buckets (or slots) holding no entry and no tombstone.
+	EmptyBuckets int `json:"empty_buckets"`
+	Tombstones   int `json:"tombstones"`
+	// Stashed counts a cuckoo table's entries that fit in neither of their
+	// slots. A lookup of one probes both slots and then the stash in order.
+	Stashed int `json:"stashed"`
+	// ChainLengths[n] is the number of buckets whose chain holds n entries. For
+	// open addressing, ChainLengths[0] counts empty slots and the rest count
+	// clusters, maximal runs of n consecutive non-empty slots, which are what
+	// probe sequences have to walk through.
+	ChainLengths []int `json:"chain_lengths"`
+	// ProbeLengths[n] is the number of keys a successful search finds after
+	// examining n nodes (or slots).
+	ProbeLengths   []int   `json:"probe_lengths"`
+	AvgProbeLength float64 `json:"avg_probe_length"`
+	MaxProbeLength int     `json:"max_probe_length"`
+	// ObservedCollisionRate is the fraction of keys whose home bucket (the first
+	// bucket they hash to) is shared with an earlier key. ExpectedCollisionRate
+	// is that fraction for a hash that spreads keys uniformly at random; an
+	// observed rate well above it points at a bad hash function.
+	ObservedCollisionRate float64 `json:"observed_collision_rate"`
+	ExpectedCollisionRate float64 `json:"expected_collision_rate"`
+}
+
+// MarshalText renders the strategy by name, e.g. in JSON-encoded stats.
+func (s CollisionStrategy) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Stats walks the whole table and reports its chain (or cluster) and probe
+// length distributions and collision rates. It completes any in-progress
+// incremental rehash first, so the figures describe a single table.
+func (ht *HashTable[K, V]) Stats() HashTableStats {
+	ht.finishRehash()
+	stats := HashTableStats{
+		Strategy:   ht.strategy,
+		Capacity:   ht.capacity,
+		Size:       ht.size,
+		LoadFactor: float64(ht.size) / float64(ht.capacity),
+		Tombstones: ht.tombstones,
+		Stashed:    len(ht.stash),
+	}
+	homes := make([]int, ht.capacity)
+	probes := 0
+	addProbe := func(n int) {
+		stats.ProbeLengths = countLength(stats.ProbeLengths, n)
+		stats.MaxProbeLength = max(stats.MaxProbeLength, n)
+		probes += n
+	}
+
+	if ht.strategy.openAddressing() {
+		stats.ChainLengths = []int{0}
+		// Start just after an empty slot, so that a cluster wrapping around the end
+		// of the slot array is counted once.
+		start := max(slices.IndexFunc(ht.slots, func(s hashSlot[K, V]) bool { return s.state == slotEmpty }), 0)
+		run := 0
+		for i := 1; i <= ht.capacity; i++ {
+			slot := ht.slots[(start+i)%ht.capacity]
+			if slot.state == slotEmpty {
+				stats.EmptyBuckets++
+				stats.ChainLengths[0]++
+				if run > 0 {
+					stats.ChainLengths = countLength(stats.ChainLengths, run)
+				}
+				run = 0
+				continue
+			}
+			if slot.state == slotOccupied {
+				homes[slot.hash&uint64(ht.capacity-1)]++
+				addProbe(slot.dist + 1)
+			}
+			run++
+		}
+		if run > 0 {
+			stats.ChainLengths = countLength(stats.ChainLengths, run)
+		}
+		for i, slot := range ht.stash {
+			homes[slot.hash&uint64(ht.capacity-1)]++
+			addProbe(2 + i + 1)
+		}
+	} else {
+		for i, node := range ht.table {
+			length := 0
+			for ; node != nil; node = node.next {
+				length++
+				addProbe(length)
+			}
+			homes[i] = length
+			if length == 0 {
+				stats.EmptyBuckets++
+			}
+			stats.ChainLengths = countLength(stats.ChainLengths, length)
+		}
+	}
+
+	if ht.size > 0 {
+		stats.AvgProbeLength = float64(probes) / float64(ht.size)
+		shared := 0
+		for _, n := range homes {
+			shared += max(n-1, 0)
+		}
+		stats.ObservedCollisionRate = float64(shared) / float64(ht.size)
+		// With n keys hashed uniformly into m buckets, m(1 - (1-1/m)^n) buckets
+		// are expected to be used; every other key lands in a used one.
+		n, m := float64(ht.size), float64(ht.capacity)
+		stats.ExpectedCollisionRate = 1 - m*(1-math.Pow(1-1/m, n))/n
+	}
+	return stats
+}
+
+// countLength increments histogram[n], growing histogram as needed.
+func countLength(histogram []int, n int) []int {
+	for len(histogram) <= n {
+		histogram = append(histogram, 0)
+	}
+	histogram[n]++
+	return histogram
+}
+
+// Table renders the stats as two columnized tables: a summary, followed by
+// the chain and probe length histograms side by side.
+func (s HashTableStats) Table() string {
+	summary := []string{
+		"\x1fStat\x1fValue\x1f",
+		fmt.Sprintf("\x1fstrategy\x1f%s\x1f", s.Strategy),
+		fmt.Sprintf("\x1fcapacity\x1f%d\x1f", s.Capacity),
+		fmt.Sprintf("\x1fsize\x1f%d\x1f", s.Size),
+		fmt.Sprintf("\x1fload factor\x1f%.3f\x1f", s.LoadFactor),
+		fmt.Sprintf("\x1fempty buckets\x1f%d\x1f", s.EmptyBuckets),
+		fmt.Sprintf("\x1ftombstones\x1f%d\x1f", s.Tombstones),
+		fmt.Sprintf("\x1fstashed\x1f%d\x1f", s.Stashed),
+		fmt.Sprintf("\x1favg probe length\x1f%.3f\x1f", s.AvgProbeLength),
+		fmt.Sprintf("\x1fmax probe length\x1f%d\x1f", s.MaxProbeLength),
+		fmt.Sprintf("\x1fcollision rate\x1f%.3f (expected %.3f)\x1f", s.ObservedCollisionRate, s.ExpectedCollisionRate),
+	}
+	chainHeader := "Chains"
+	if s.Strategy.openAddressing() {
+		chainHeader = "Clusters"
+	}
+	histogram := []string{fmt.Sprintf("\x1fLength\x1f%s\x1fProbes\x1f", chainHeader)}
+	for n := 0; n < max(len(s.ChainLengths), len(s.ProbeLengths)); n++ {
+		histogram = append(histogram, fmt.Sprintf("\x1f%d\x1f%d\x1f%d\x1f", n, histogramAt(s.ChainLengths, n), histogramAt(s.ProbeLengths, n)))
+	}
+	config := &columnize.Config{Delim: string([]byte{0x1f}), Glue: " "}
+	return strings.Join([]string{columnize.Format(summary, config), columnize.Format(histogram, config)}, "\n\n")
+}
+
+func histogramAt(histogram []int, n int) int {
+	if n < len(histogram) {
+		return histogram[n]
+	}
+	return 0
+}
+
+// StatsFormat selects whether, and how, Print appends the table's Stats.
+type StatsFormat int
+
+const (
+	// NoStats prints only the table's contents.
+	NoStats StatsFormat = iota
+	// StatsTable appends the stats as columnized tables.
+	StatsTable
+	// StatsJSON appends the stats as indented JSON.
+	StatsJSON
+)
+
+// printStats prints the table's stats in the given format.
+func (ht *HashTable[K, V]) printStats(format StatsFormat) {
+	switch format {
+	case StatsTable:
+		fmt.Println(ht.Stats().Table())
+	case StatsJSON:
+		out, err := json.MarshalIndent(ht.Stats(), "", "  ")
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(string(out))
+	}
+}
@@ -0,0 +1,148 @@
+package datastructures
+
+import "iter"
+
+// Iterator is a cursor over the items of a container.
+//
+// A new iterator is positioned before the first item: call Next to advance, and
+// Value to read the item the cursor is on. Next returns false once the items are
+// exhausted, after which Value returns the zero value. The result of modifying a
+// container while iterating over it is undefined.
+//
+//	for it := list.Iterator(); it.Next(); {
+//		fmt.Println(it.Value())
+//	}
+type Iterator[T any] interface {
+	// Next advances the cursor, returning false when no items remain.
+	Next() bool
+	// Value returns the item under the cursor.
+	Value() T
+}
+
+// Iterable is implemented by containers that can be walked in their natural order.
+type Iterable[T any] interface {
+	Iterator() Iterator[T]
+}
+
+// ReverseIterable is implemented by containers that can also be walked backwards.
+type ReverseIterable[T any] interface {
+	ReverseIterator() Iterator[T]
+}
+
+// Entry is a key-value pair produced by iterating over a map-like container.
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// Seq adapts an iterator to an iter.Seq for use with range-over-func:
+//
+//	for v := range datastructures.Seq(list.Iterator()) { ... }
+func Seq[T any](it Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2 adapts an iterator to an iter.Seq2 that pairs each item with its
+// zero-based position in the iteration.
+func Seq2[T any](it Iterator[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; it.Next(); i++ {
+			if !yield(i, it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains an iterator into a slice.
+func Collect[T any](it Iterator[T]) []T {
+	var out []T
+	for it.Next() {
+		out = append(out, it.Value())
+	}
+	return out
+}
+
+// NewIterator builds an Iterator from a function that returns the next item,
+// and false once there are none left. It lets containers (including those in
+// other packages) implement Iterator with a closure over their own cursor state.
+func NewIterator[T any](next func() (T, bool)) Iterator[T] {
+	return &funcIterator[T]{next: next}
+}
+
+type funcIterator[T any] struct {
+	next    func() (T, bool)
+	current T
+	done    bool
+}
+
+func (it *funcIterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	value, ok := it.next()
+	if !ok {
+		var zero T
+		it.current = zero
+		it.done = true
+		return false
+	}
+	it.current = value
+	return true
+}
+
+func (it *funcIterator[T]) Value() T {
+	return it.current
+}
+
+// indexIterator walks positions 0..n-1 (or n-1..0 in reverse) of an indexable
+// container, reading each item through at.
+func indexIterator[T any](n int, reverse bool, at func(i int) T) Iterator[T] {
+	i, step := -1, 1
+	if reverse {
+		i, step = n, -1
+	}
+	return NewIterator(func() (T, bool) {
+		i += step
+		if i < 0 || i >= n {
+			var zero T
+			return zero, false
+		}
+		return at(i), true
+	})
+}
+
+// InorderIterator walks the binary tree rooted at root in-order without
+// recursion, keeping the path of not-yet-visited ancestors on an explicit
+// stack. left and right return a node's children, isNil reports whether a
+// child is absent, and value reads a node's item. In reverse the roles of the
+// left and right children are swapped.
+func InorderIterator[N, T any](root N, left, right func(N) N, isNil func(N) bool, value func(N) T, reverse bool) Iterator[T] {
+	near, far := left, right
+	if reverse {
+		near, far = far, near
+	}
+	var stack []N
+	pushNear := func(n N) {
+		for ; !isNil(n); n = near(n) {
+			stack = append(stack, n)
+		}
+	}
+	pushNear(root)
+	return NewIterator(func() (T, bool) {
+		if len(stack) == 0 {
+			var zero T
+			return zero, false
+		}
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		pushNear(far(n))
+		return value(n), true
+	})
+}
@@ -0,0 +1,338 @@
+package datastructures
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// List is the ordered-list ADT shared by ArrayList, LinkedList and DoublyLinkedList,
+// so callers can swap one implementation for another.
+//
+// Indexes are zero-based. Operations given an index outside the list return
+// ErrIndexOutOfRange and leave the list unchanged. Comparators follow the
+// cmp.Compare convention: negative when a < b, zero when equal, positive when a > b.
+type List[T any] interface {
+	// Len returns the number of items in the list.
+	Len() int
+	// IsEmpty returns true if the list holds no items.
+	IsEmpty() bool
+	// Get returns the item at index.
+	Get(index int) (T, error)
+	// Set replaces the item at index.
+	Set(index int, value T) error
+	// Append adds values, in order, to the end of the list.
+	Append(values ...T)
+	// Prepend adds value to the front of the list.
+	Prepend(value T)
+	// Insert places value at index; an index equal to Len appends.
+	Insert(index int, value T) error
+	// RemoveAt removes and returns the item at index.
+	RemoveAt(index int) (T, error)
+	// Search returns the index of the first item equal to target, or -1.
+	Search(target T, compare func(a, b T) int) int
+	// Clear removes every item.
+	Clear()
+	// ToSlice returns a copy of the items, in order.
+	ToSlice() []T
+	// Iterator returns an iterator over the items, in order.
+	Iterator() Iterator[T]
+}
+
+var (
+	_ List[int] = (*ArrayList[int])(nil)
+	_ List[int] = (*LinkedList[int])(nil)
+	_ List[int] = (*DoublyLinkedList[int])(nil)
+)
+
+// conformanceOps is the number of random operations CheckList replays against
+// both the list under test and a plain slice model.
+const conformanceOps = 2000
+
+// CheckList runs the List conformance suite against lists created by newList.
+// Each check gets a fresh, empty list. It returns nil if the implementation
+// behaves as the List documentation specifies, otherwise an error describing
+// every violation found.
+//
+// Any List implementation can be verified with it, e.g. from a test:
+//
+//	if err := datastructures.CheckList(func() datastructures.List[int] { return NewMyList() }); err != nil {
+//		t.Fatal(err)
+//	}
+func CheckList(newList func() List[int]) error {
+	checks := []struct {
+		name string
+		run  func(List[int]) error
+	}{
+		{"empty", checkListEmpty},
+		{"append-prepend", checkListAppendPrepend},
+		{"get-set", checkListGetSet},
+		{"insert", checkListInsert},
+		{"remove", checkListRemove},
+		{"search", checkListSearch},
+		{"clear", checkListClear},
+		{"to-slice-copy", checkListToSliceCopy},
+		{"iterator", checkListIterator},
+		{"random-model", checkListRandomModel},
+	}
+	var errs []error
+	for _, check := range checks {
+		if err := check.run(newList()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", check.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expectContents verifies that list holds exactly want, through every read accessor.
+func expectContents(list List[int], want []int) error {
+	if got := list.ToSlice(); !slices.Equal(got, want) {
+		return fmt.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+	if list.Len() != len(want) {
+		return fmt.Errorf("Len() = %d, want %d", list.Len(), len(want))
+	}
+	if list.IsEmpty() != (len(want) == 0) {
+		return fmt.Errorf("IsEmpty() = %v with %d items", list.IsEmpty(), len(want))
+	}
+	for i, w := range want {
+		if got, err := list.Get(i); err != nil || got != w {
+			return fmt.Errorf("Get(%d) = %d, %v, want %d", i, got, err, w)
+		}
+	}
+	return nil
+}
+
+func checkListEmpty(list List[int]) error {
+	if err := expectContents(list, nil); err != nil {
+		return err
+	}
+	if _, err := list.Get(0); !errors.Is(err, ErrIndexOutOfRange) {
+		return fmt.Errorf("Get(0) on empty list: err = %v, want ErrIndexOutOfRange", err)
+	}
+	if _, err := list.RemoveAt(0); !errors.Is(err, ErrIndexOutOfRange) {
+		return fmt.Errorf("RemoveAt(0) on empty list: err = %v, want ErrIndexOutOfRange", err)
+	}
+	if idx := list.Search(1, cmp.Compare[int]); idx != -1 {
+		return fmt.Errorf("Search(1) on empty list = %d, want -1", idx)
+	}
+	return nil
+}
+
+func checkListAppendPrepend(list List[int]) error {
+	list.Append(3, 4)
+	list.Prepend(2)
+	list.Append(5)
+	list.Prepend(1)
+	list.Append()
+	return expectContents(list, []int{1, 2, 3, 4, 5})
+}
+
+func checkListGetSet(list List[int]) error {
+	list.Append(10, 20, 30)
+	if err := list.Set(1, 25); err != nil {
+		return fmt.Errorf("Set(1): %v", err)
+	}
+	for _, index := range []int{-1, 3} {
+		if err := list.Set(index, 0); !errors.Is(err, ErrIndexOutOfRange) {
+			return fmt.Errorf("Set(%d): err = %v, want ErrIndexOutOfRange", index, err)
+		}
+		if _, err := list.Get(index); !errors.Is(err, ErrIndexOutOfRange) {
+			return fmt.Errorf("Get(%d): err = %v, want ErrIndexOutOfRange", index, err)
+		}
+	}
+	return expectContents(list, []int{10, 25, 30})
+}
+
+func checkListInsert(list List[int]) error {
+	steps := []struct{ index, value int }{
+		{0, 2}, // into empty list
+		{0, 0}, // front
+		{1, 1}, // middle
+		{3, 4}, // end (index == Len)
+		{3, 3}, // before the tail
+	}
+	for _, step := range steps {
+		if err := list.Insert(step.index, step.value); err != nil {
+			return fmt.Errorf("Insert(%d, %d): %v", step.index, step.value, err)
+		}
+	}
+	for _, index := range []int{-1, 6} {
+		if err := list.Insert(index, 99); !errors.Is(err, ErrIndexOutOfRange) {
+			return fmt.Errorf("Insert(%d): err = %v, want ErrIndexOutOfRange", index, err)
+		}
+	}
+	if err := expectContents(list, []int{0, 1, 2, 3, 4}); err != nil {
+		return err
+	}
+	// Appending after inserting at the end must extend past the new tail.
+	list.Append(5)
+	return expectContents(list, []int{0, 1, 2, 3, 4, 5})
+}
+
+func checkListRemove(list List[int]) error {
+	list.Append(0, 1, 2, 3, 4)
+	for _, step := range []struct{ index, want int }{{4, 4}, {0, 0}, {1, 2}} {
+		got, err := list.RemoveAt(step.index)
+		if err != nil || got != step.want {
+			return fmt.Errorf("RemoveAt(%d) = %d, %v, want %d", step.index, got, err, step.want)
+		}
+	}
+	if _, err := list.RemoveAt(2); !errors.Is(err, ErrIndexOutOfRange) {
+		return fmt.Errorf("RemoveAt(2) past end: err = %v, want ErrIndexOutOfRange", err)
+	}
+	if err := expectContents(list, []int{1, 3}); err != nil {
+		return err
+	}
+	// Removing the tail must leave a list that can still be appended to.
+	if _, err := list.RemoveAt(1); err != nil {
+		return fmt.Errorf("RemoveAt(1): %v", err)
+	}
+	list.Append(7)
+	if err := expectContents(list, []int{1, 7}); err != nil {
+		return err
+	}
+	_, _ = list.RemoveAt(0)
+	_, _ = list.RemoveAt(0)
+	list.Prepend(9)
+	return expectContents(list, []int{9})
+}
+
+func checkListSearch(list List[int]) error {
+	list.Append(5, 7, 5, 9)
+	for _, step := range []struct{ target, want int }{{5, 0}, {7, 1}, {9, 3}, {8, -1}} {
+		if got := list.Search(step.target, cmp.Compare[int]); got != step.want {
+			return fmt.Errorf("Search(%d) = %d, want %d", step.target, got, step.want)
+		}
+	}
+	return nil
+}
+
+func checkListClear(list List[int]) error {
+	list.Append(1, 2, 3)
+	list.Clear()
+	if err := expectContents(list, nil); err != nil {
+		return err
+	}
+	list.Append(4)
+	return expectContents(list, []int{4})
+}
+
+func checkListToSliceCopy(list List[int]) error {
+	list.Append(1, 2, 3)
+	out := list.ToSlice()
+	out[0] = 100
+	return expectContents(list, []int{1, 2, 3})
+}
+
+func checkListIterator(list List[int]) error {
+	if got := Collect(list.Iterator()); len(got) != 0 {
+		return fmt.Errorf("Iterator() on empty list yielded %v", got)
+	}
+	list.Append(1, 2, 3)
+	if got := Collect(list.Iterator()); !slices.Equal(got, []int{1, 2, 3}) {
+		return fmt.Errorf("Iterator() yielded %v, want [1 2 3]", got)
+	}
+	if reversible, ok := list.(ReverseIterable[int]); ok {
+		if got := Collect(reversible.ReverseIterator()); !slices.Equal(got, []int{3, 2, 1}) {
+			return fmt.Errorf("ReverseIterator() yielded %v, want [3 2 1]", got)
+		}
+	}
+	// Stopping a range loop early must not yield further items.
+	var seen []int
+	for i, v := range Seq2(list.Iterator()) {
+		if i == 2 {
+			break
+		}
+		seen = append(seen, v)
+	}
+	if !slices.Equal(seen, []int{1, 2}) {
+		return fmt.Errorf("Seq2 with break yielded %v, want [1 2]", seen)
+	}
+	return nil
+}
+
+// checkListRandomModel replays a fixed pseudo-random sequence of operations on
+// the list and on a slice, comparing the two after every step.
+func checkListRandomModel(list List[int]) error {
+	rng := rand.New(rand.NewSource(1))
+	var model []int
+	for op := 0; op < conformanceOps; op++ {
+		value := rng.Intn(50)
+		index := rng.Intn(len(model)+2) - 1 // Occasionally out of range on either side
+		var desc string
+		switch rng.Intn(6) {
+		case 0:
+			desc = fmt.Sprintf("Append(%d)", value)
+			list.Append(value)
+			model = append(model, value)
+		case 1:
+			desc = fmt.Sprintf("Prepend(%d)", value)
+			list.Prepend(value)
+			model = slices.Insert(model, 0, value)
+		case 2:
+			desc = fmt.Sprintf("Insert(%d, %d)", index, value)
+			err := list.Insert(index, value)
+			if index >= 0 && index <= len(model) {
+				if err != nil {
+					return fmt.Errorf("op %d %s: %v", op, desc, err)
+				}
+				model = slices.Insert(model, index, value)
+			} else if !errors.Is(err, ErrIndexOutOfRange) {
+				return fmt.Errorf("op %d %s: err = %v, want ErrIndexOutOfRange", op, desc, err)
+			}
+		case 3:
+			desc = fmt.Sprintf("RemoveAt(%d)", index)
+			got, err := list.RemoveAt(index)
+			if index >= 0 && index < len(model) {
+				if err != nil || got != model[index] {
+					return fmt.Errorf("op %d %s = %d, %v, want %d", op, desc, got, err, model[index])
+				}
+				model = slices.Delete(model, index, index+1)
+			} else if !errors.Is(err, ErrIndexOutOfRange) {
+				return fmt.Errorf("op %d %s: err = %v, want ErrIndexOutOfRange", op, desc, err)
+			}
+		case 4:
+			desc = fmt.Sprintf("Set(%d, %d)", index, value)
+			err := list.Set(index, value)
+			if index >= 0 && index < len(model) {
+				if err != nil {
+					return fmt.Errorf("op %d %s: %v", op, desc, err)
+				}
+				model[index] = value
+			} else if !errors.Is(err, ErrIndexOutOfRange) {
+				return fmt.Errorf("op %d %s: err = %v, want ErrIndexOutOfRange", op, desc, err)
+			}
+		case 5:
+			desc = fmt.Sprintf("Search(%d)", value)
+			if got, want := list.Search(value, cmp.Compare[int]), slices.Index(model, value); got != want {
+				return fmt.Errorf("op %d %s = %d, want %d", op, desc, got, want)
+			}
+		}
+		if got := list.ToSlice(); !slices.Equal(got, model) || list.Len() != len(model) {
+			return fmt.Errorf("after op %d %s: ToSlice() = %v, Len() = %d, want %v", op, desc, got, list.Len(), model)
+		}
+	}
+	return expectContents(list, model)
+}
+
+// TestList runs the conformance suite against every List implementation in this package.
+func TestList() {
+	implementations := []struct {
+		name    string
+		newList func() List[int]
+	}{
+		{"ArrayList", func() List[int] { return NewArrayList[int]() }},
+		{"LinkedList", func() List[int] { return NewLinkedList[int]() }},
+		{"DoublyLinkedList", func() List[int] { return NewLinkedListDouble[int]() }},
+	}
+	for _, impl := range implementations {
+		if err := CheckList(impl.newList); err != nil {
+			fmt.Printf("%s: FAIL\n%v\n", impl.name, err)
+			continue
+		}
+		fmt.Printf("%s: ok\n", impl.name)
+	}
+}
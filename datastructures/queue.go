@@ -1,88 +1,183 @@
 package datastructures
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrQueueFull is returned by Enqueue on a bounded queue that rejects items when full.
+var ErrQueueFull = errors.New("queue is full")
+
+// OverflowPolicy decides what a bounded queue does when an item is enqueued while full.
+type OverflowPolicy int
+
+const (
+	// RejectWhenFull makes Enqueue return ErrQueueFull and leave the queue unchanged.
+	RejectWhenFull OverflowPolicy = iota
+	// EvictOldest makes Enqueue drop the front item to make room for the new one.
+	EvictOldest
+)
+
+// Queue is a FIFO queue backed by a growable circular buffer: Enqueue and Dequeue
+// are O(1) amortized, and the buffer shrinks as the queue drains so memory is
+// reclaimed. By default a queue is unbounded; WithCapacity caps it.
+type Queue[T any] struct {
+	items    ring[T]
+	capacity int // 0 means unbounded
+	overflow OverflowPolicy
+}
+
+// QueueOption configures a Queue created by NewQueue.
+type QueueOption func(*queueOptions)
 
-type Queue struct {
-	items []QueueItem
+type queueOptions struct {
+	capacity int
+	overflow OverflowPolicy
 }
 
-type QueueItem interface{}
+// WithCapacity bounds the queue to capacity items, applying policy when it is full.
+// A capacity <= 0 leaves the queue unbounded.
+func WithCapacity(capacity int, policy OverflowPolicy) QueueOption {
+	return func(o *queueOptions) {
+		o.capacity = max(capacity, 0)
+		o.overflow = policy
+	}
+}
 
-func NewQueue() *Queue {
-	return &Queue{}
+// NewQueue creates a new empty queue.
+func NewQueue[T any](opts ...QueueOption) *Queue[T] {
+	var o queueOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Queue[T]{capacity: o.capacity, overflow: o.overflow}
 }
 
-// Enqueue adds an item to the end of the queue.
-func (q *Queue) Enqueue(item QueueItem) {
-	q.items = append(q.items, item)
+// Enqueue adds an item to the end of the queue. On a full bounded queue it either
+// returns ErrQueueFull or evicts the front item, depending on the overflow policy.
+func (q *Queue[T]) Enqueue(item T) error {
+	if q.IsFull() {
+		if q.overflow == RejectWhenFull {
+			return ErrQueueFull
+		}
+		q.items.popFront()
+	}
+	q.items.pushBack(item)
+	return nil
 }
 
 // Dequeue removes and returns the front item from the queue.
-func (q *Queue) Dequeue() QueueItem {
-	if len(q.items) == 0 {
-		return nil
+// It returns false if the queue is empty.
+func (q *Queue[T]) Dequeue() (T, bool) {
+	if q.items.len() == 0 {
+		var zero T
+		return zero, false
 	}
-	front := q.items[0]
-	q.items = q.items[1:]
-	return front
+	return q.items.popFront(), true
 }
 
 // Peek returns the front item of the queue without removing it.
-func (q *Queue) Peek() QueueItem {
-	if len(q.items) == 0 {
-		return nil
+// It returns false if the queue is empty.
+func (q *Queue[T]) Peek() (T, bool) {
+	if q.items.len() == 0 {
+		var zero T
+		return zero, false
 	}
-	return q.items[0]
+	return q.items.at(0), true
 }
 
 // IsEmpty returns true if the queue is empty, false otherwise.
-func (q *Queue) IsEmpty() bool {
-	return len(q.items) == 0
+func (q *Queue[T]) IsEmpty() bool {
+	return q.items.len() == 0
+}
+
+// IsFull returns true if the queue is bounded and holds capacity items.
+func (q *Queue[T]) IsFull() bool {
+	return q.capacity > 0 && q.items.len() >= q.capacity
 }
 
 // GetLength returns the number of items in the queue.
-func (q *Queue) GetLength() int {
-	return len(q.items)
+func (q *Queue[T]) GetLength() int {
+	return q.items.len()
+}
+
+// Capacity returns the queue's bound, or 0 if it is unbounded.
+func (q *Queue[T]) Capacity() int {
+	return q.capacity
+}
+
+// Clear removes every item and releases the queue's buffer.
+func (q *Queue[T]) Clear() {
+	q.items.clear()
+}
+
+// Iterator returns an iterator over the items from front to back.
+func (q *Queue[T]) Iterator() Iterator[T] {
+	return indexIterator(q.items.len(), false, q.items.at)
+}
+
+// ReverseIterator returns an iterator over the items from back to front.
+func (q *Queue[T]) ReverseIterator() Iterator[T] {
+	return indexIterator(q.items.len(), true, q.items.at)
 }
 
 // Print prints the items in the queue from front to back.
-func (q *Queue) Print() {
-	fmt.Printf("%v\n", q.items)
+func (q *Queue[T]) Print() {
+	fmt.Printf("%v\n", q.items.toSlice())
 }
 
-func TestQueue(initQueue []QueueItem) {
-	queue := &Queue{items: initQueue}
+func TestQueue(initQueue []int) {
+	queue := NewQueue[int]()
+	for _, item := range initQueue {
+		_ = queue.Enqueue(item)
+	}
 	fmt.Printf("initial queue: ")
 	queue.Print()
 
-	//peaker := queue.Peek()
+	//peaker, _ := queue.Peek()
 	//fmt.Printf("peak test -> peaker %v -> queue: ", peaker)
 	//queue.Print()
 	//
-	queue.Enqueue(11)
+	_ = queue.Enqueue(11)
 	fmt.Printf("enqueue test -> queue: ")
 	queue.Print()
 
-	queue.Enqueue(34)
+	_ = queue.Enqueue(34)
 	fmt.Printf("enqueue test -> queue: ")
 	queue.Print()
 
-	removed := queue.Dequeue()
+	removed, _ := queue.Dequeue()
 	fmt.Printf("dequeue test -> removed %v -> queue: ", removed)
 	queue.Print()
 	//
-	//queue.Enqueue(81)
+	//_ = queue.Enqueue(81)
 	//fmt.Printf("enqueue test -> queue: ")
 	//queue.Print()
 	//
-	//peaker = queue.Peek()
+	//peaker, _ = queue.Peek()
 	//fmt.Printf("peak test -> peaker %v -> queue: ", peaker)
 	//queue.Print()
 
-	removed = queue.Dequeue()
+	removed, _ = queue.Dequeue()
 	fmt.Printf("dequeue test -> removed %v -> queue: ", removed)
 	queue.Print()
 
 	fmt.Println("queue length ->", queue.GetLength())
 	fmt.Println("queue isEmpty ->", queue.IsEmpty())
+
+	bounded := NewQueue[int](WithCapacity(2, RejectWhenFull))
+	for _, item := range []int{1, 2, 3} {
+		if err := bounded.Enqueue(item); err != nil {
+			fmt.Printf("bounded enqueue %d -> %v\n", item, err)
+		}
+	}
+	fmt.Printf("bounded (reject) queue: ")
+	bounded.Print()
+
+	evicting := NewQueue[int](WithCapacity(2, EvictOldest))
+	for _, item := range []int{1, 2, 3} {
+		_ = evicting.Enqueue(item)
+	}
+	fmt.Printf("bounded (evict) queue: ")
+	evicting.Print()
 }
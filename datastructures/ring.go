@@ -0,0 +1,151 @@
+package datastructures
+
+// minRingCapacity is the smallest allocation a ring buffer shrinks back down to.
+const minRingCapacity = 8
+
+// ring is a growable circular buffer. Items occupy size consecutive slots starting
+// at head and wrapping around the end of buf, so both ends can be pushed or popped
+// in O(1) without shifting. The buffer doubles when full and halves once it drains
+// to a quarter of its allocation, so long-running producer/consumer use does not
+// pin a backing array sized for the historical peak.
+type ring[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+// len returns the number of items in the buffer.
+func (r *ring[T]) len() int {
+	return r.size
+}
+
+// index maps a logical position (0 == head) to a slot in buf.
+func (r *ring[T]) index(i int) int {
+	return (r.head + i) % len(r.buf)
+}
+
+// at returns the item at logical position i.
+func (r *ring[T]) at(i int) T {
+	return r.buf[r.index(i)]
+}
+
+// set replaces the item at logical position i.
+func (r *ring[T]) set(i int, value T) {
+	r.buf[r.index(i)] = value
+}
+
+// pushBack adds value after the last item.
+func (r *ring[T]) pushBack(value T) {
+	if r.size == len(r.buf) {
+		r.resize(max(len(r.buf)*2, minRingCapacity))
+	}
+	r.buf[r.index(r.size)] = value
+	r.size++
+}
+
+// pushFront adds value before the first item.
+func (r *ring[T]) pushFront(value T) {
+	if r.size == len(r.buf) {
+		r.resize(max(len(r.buf)*2, minRingCapacity))
+	}
+	r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+	r.buf[r.head] = value
+	r.size++
+}
+
+// popFront removes and returns the first item. The buffer must not be empty.
+func (r *ring[T]) popFront() T {
+	var zero T
+	value := r.buf[r.head]
+	r.buf[r.head] = zero // Release the reference so it can be collected
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+	r.shrink()
+	return value
+}
+
+// popBack removes and returns the last item. The buffer must not be empty.
+func (r *ring[T]) popBack() T {
+	var zero T
+	last := r.index(r.size - 1)
+	value := r.buf[last]
+	r.buf[last] = zero // Release the reference so it can be collected
+	r.size--
+	r.shrink()
+	return value
+}
+
+// rotate moves the last k items to the front (k > 0) or the first -k items to
+// the back (k < 0), keeping the items' cyclic order.
+func (r *ring[T]) rotate(k int) {
+	if r.size < 2 {
+		return
+	}
+	k %= r.size
+	if k < 0 {
+		k += r.size
+	}
+	if r.size == len(r.buf) {
+		// Every slot is in use, so rotating is just a matter of moving head.
+		r.head = (r.head - k + len(r.buf)) % len(r.buf)
+		return
+	}
+	// Otherwise move items one at a time through the free slots on either side,
+	// going whichever way around is shorter.
+	var zero T
+	if k <= r.size/2 {
+		for ; k > 0; k-- {
+			last := r.index(r.size - 1)
+			r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+			r.buf[r.head], r.buf[last] = r.buf[last], zero
+		}
+		return
+	}
+	for k = r.size - k; k > 0; k-- {
+		first := r.head
+		r.buf[r.index(r.size)], r.buf[first] = r.buf[first], zero
+		r.head = (r.head + 1) % len(r.buf)
+	}
+}
+
+// reverse reverses the order of the items in place.
+func (r *ring[T]) reverse() {
+	for i, j := 0, r.size-1; i < j; i, j = i+1, j-1 {
+		a, b := r.index(i), r.index(j)
+		r.buf[a], r.buf[b] = r.buf[b], r.buf[a]
+	}
+}
+
+// clear removes every item and releases the allocation.
+func (r *ring[T]) clear() {
+	*r = ring[T]{}
+}
+
+// toSlice copies the items, in order, into a new slice.
+func (r *ring[T]) toSlice() []T {
+	out := make([]T, r.size)
+	for i := range out {
+		out[i] = r.at(i)
+	}
+	return out
+}
+
+// shrink halves the allocation once the buffer has drained to a quarter full.
+func (r *ring[T]) shrink() {
+	if len(r.buf) > minRingCapacity && r.size <= len(r.buf)/4 {
+		r.resize(len(r.buf) / 2)
+	}
+}
+
+// resize moves the items into a new buffer of the given capacity, unwrapping
+// them so that head is back at slot 0.
+func (r *ring[T]) resize(capacity int) {
+	buf := make([]T, capacity)
+	if r.size > 0 {
+		// Copy the run from head to the end of buf, then the wrapped-around run.
+		n := copy(buf, r.buf[r.head:min(r.head+r.size, len(r.buf))])
+		copy(buf[n:], r.buf[:r.size-n])
+	}
+	r.buf = buf
+	r.head = 0
+}
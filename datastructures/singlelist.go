@@ -1,6 +1,7 @@
 package datastructures
 
 import (
+	"cmp"
 	"fmt"
 	"reflect"
 )
@@ -48,211 +49,310 @@ import (
 	 * Dictionary (Map): an ADT that associates (maps) keys => values
 */
 
-// NodeData represents the data type for the linked list.
-type NodeData interface{}
-
 // Node represents a node in the singly-linked list
-type Node struct {
-	Data NodeData
-	Next *Node
+type Node[T any] struct {
+	Data T
+	next *Node[T]
+}
+
+// Next returns the node after n, or nil if n is the tail.
+func (n *Node[T]) Next() *Node[T] {
+	return n.next
 }
 
-// LinkedList represents a singly-linked list
-type LinkedList struct {
-	Head *Node
-	Tail *Node
+// LinkedList represents a singly-linked list. Its nodes are linked only by its
+// own methods, which keep its length up to date.
+type LinkedList[T any] struct {
+	head *Node[T]
+	tail *Node[T]
+	size int
 }
 
 // NewLinkedList creates a new empty linked list
-func NewLinkedList() *LinkedList {
-	return &LinkedList{}
+func NewLinkedList[T any]() *LinkedList[T] {
+	return &LinkedList[T]{}
 }
 
-// Append adds a new node with the given data to the end of the list
-func (l *LinkedList) Append(data NodeData) {
-	newNode := &Node{Data: data}
+// Head returns the first node of the list, or nil if it is empty.
+func (l *LinkedList[T]) Head() *Node[T] {
+	return l.head
+}
 
-	if l.Head == nil {
-		l.Head = newNode
-		l.Tail = newNode
-		return
-	}
+// Tail returns the last node of the list, or nil if it is empty.
+func (l *LinkedList[T]) Tail() *Node[T] {
+	return l.tail
+}
+
+// Len returns the number of nodes in the list.
+func (l *LinkedList[T]) Len() int {
+	return l.size
+}
 
-	l.Tail.Next = newNode
-	l.Tail = newNode
+// IsEmpty returns true if the list has no nodes.
+func (l *LinkedList[T]) IsEmpty() bool {
+	return l.size == 0
 }
 
-// ListPrepend inserts a new node at the beginning of the list.
-func (l *LinkedList) Prepend(data NodeData) {
-	newNode := &Node{Data: data}
-	newNode.Next = l.Head
-	l.Head = newNode
-	if l.Tail == nil {
-		l.Tail = newNode
+// Append adds new nodes with the given data to the end of the list
+func (l *LinkedList[T]) Append(values ...T) {
+	for _, data := range values {
+		newNode := &Node[T]{Data: data}
+		if l.head == nil {
+			l.head = newNode
+		} else {
+			l.tail.next = newNode
+		}
+		l.tail = newNode
+		l.size++
 	}
 }
 
-// Delete removes the first occurrence of a node with the given data from the list
-func (l *LinkedList) Delete(data NodeData) {
-	if l.Head == nil {
-		return
+// Prepend inserts a new node at the beginning of the list.
+func (l *LinkedList[T]) Prepend(data T) {
+	newNode := &Node[T]{Data: data}
+	newNode.next = l.head
+	l.head = newNode
+	if l.tail == nil {
+		l.tail = newNode
 	}
+	l.size++
+}
 
-	if l.Head.Data == data {
-		if l.Head == l.Tail {
-			l.Tail = nil
-		}
-		l.Head = l.Head.Next
-		return
+// Get returns the data stored in the node at index.
+func (l *LinkedList[T]) Get(index int) (T, error) {
+	node := l.nodeAt(index)
+	if node == nil {
+		var zero T
+		return zero, ErrIndexOutOfRange
 	}
+	return node.Data, nil
+}
 
-	current := l.Head
-	for current.Next != nil {
-		if current.Next.Data == data {
-			if current.Next == l.Tail {
-				l.Tail = current
-			}
-			current.Next = current.Next.Next
-			return
-		}
-		current = current.Next
+// Set replaces the data stored in the node at index.
+func (l *LinkedList[T]) Set(index int, data T) error {
+	node := l.nodeAt(index)
+	if node == nil {
+		return ErrIndexOutOfRange
 	}
+	node.Data = data
+	return nil
 }
 
-// InsertAfter inserts a new node with the given data after the first occurrence
-// of the specified existing data in the list.
-func (l *LinkedList) InsertAfter(existingData, newData NodeData) {
-	newNode := &Node{Data: newData}
+// Insert places a new node with the given data at index. An index equal to Len appends.
+func (l *LinkedList[T]) Insert(index int, data T) error {
+	if index < 0 || index > l.size {
+		return ErrIndexOutOfRange
+	}
+	if index == 0 {
+		l.Prepend(data)
+		return nil
+	}
+	l.InsertAfter(l.nodeAt(index-1), data)
+	return nil
+}
 
-	current := l.Head
-	for current != nil {
-		if current.Data == existingData {
-			newNode.Next = current.Next
-			current.Next = newNode
-			if current == l.Tail {
-				l.Tail = newNode
-			}
-			return
+// RemoveAt removes the node at index and returns its data.
+func (l *LinkedList[T]) RemoveAt(index int) (T, error) {
+	if index < 0 || index >= l.size {
+		var zero T
+		return zero, ErrIndexOutOfRange
+	}
+	var before *Node[T]
+	if index > 0 {
+		before = l.nodeAt(index - 1)
+	}
+	return l.RemoveAfter(before), nil
+}
+
+// Delete removes the first node whose data compares equal to target.
+// It returns false if no such node exists.
+func (l *LinkedList[T]) Delete(target T, compare func(a, b T) int) bool {
+	var prev *Node[T]
+	for current := l.head; current != nil; current = current.next {
+		if compare(current.Data, target) == 0 {
+			l.RemoveAfter(prev)
+			return true
 		}
-		current = current.Next
+		prev = current
 	}
+	return false
 }
 
-// RemoveAfter removes the node immediately after the first occurrence
-// of the specified existing data in the list. If existingData is 0,
-// it removes the head of the list.
-func (l *LinkedList) RemoveAfter(existingData NodeData) {
-	if l.Head == nil {
-		// Empty list, nothing to remove
+// InsertAfter inserts a new node with the given data after the existing node.
+// A nil node prepends the new node to the list.
+func (l *LinkedList[T]) InsertAfter(existing *Node[T], data T) {
+	if existing == nil {
+		l.Prepend(data)
 		return
 	}
+	newNode := &Node[T]{Data: data, next: existing.next}
+	existing.next = newNode
+	if existing == l.tail {
+		l.tail = newNode
+	}
+	l.size++
+}
+
+// RemoveAfter removes the node immediately after the existing node and returns
+// its data. If existing is nil, it removes the head of the list.
+func (l *LinkedList[T]) RemoveAfter(existing *Node[T]) T {
+	var zero T
+	if l.head == nil {
+		// Empty list, nothing to remove
+		return zero
+	}
 
-	if existingData == 0 {
+	if existing == nil {
 		// Remove the head of the list
-		if l.Head == l.Tail {
-			l.Tail = nil
+		removed := l.head
+		if l.head == l.tail {
+			l.tail = nil
 		}
-		l.Head = l.Head.Next
-		//if l.Head != nil {
-		//	fmt.Printf("suc: %d\n", l.Head.Data)
-		//}
-		return
+		l.head = l.head.next
+		l.size--
+		return removed.Data
 	}
 
-	current := l.Head
-	for current != nil && current.Next != nil {
-		if current.Data == existingData {
-			if current.Next == l.Tail {
-				l.Tail = current
-			}
-			current.Next = current.Next.Next
-			//if current.Next != nil {
-			//	fmt.Printf("suc: %d\n", current.Next.Data)
-			//}
-			return
-		}
-		current = current.Next
+	removed := existing.next
+	if removed == nil {
+		return zero
+	}
+	if removed == l.tail {
+		l.tail = existing
 	}
+	existing.next = removed.next
+	l.size--
+	return removed.Data
 }
 
-func (l *LinkedList) ListSearch(key NodeData) *Node {
-	if l.Head == nil {
-		return nil
+// Clear removes every node from the list.
+func (l *LinkedList[T]) Clear() {
+	l.head = nil
+	l.tail = nil
+	l.size = 0
+}
+
+// Search returns the index of the first node whose data compares equal to
+// target, or -1 if no such node exists.
+func (l *LinkedList[T]) Search(target T, compare func(a, b T) int) int {
+	index := 0
+	for current := l.head; current != nil; current = current.next {
+		if compare(current.Data, target) == 0 {
+			return index
+		}
+		index++
 	}
-	current := l.Head
-	for current != nil {
-		if current.Data == key {
+	return -1
+}
+
+// Find returns the first node whose data compares equal to target, or nil.
+func (l *LinkedList[T]) Find(target T, compare func(a, b T) int) *Node[T] {
+	for current := l.head; current != nil; current = current.next {
+		if compare(current.Data, target) == 0 {
 			return current
 		}
-		current = current.Next
 	}
 	return nil
 }
 
+// ToSlice returns the data of every node, from head to tail.
+func (l *LinkedList[T]) ToSlice() []T {
+	out := make([]T, 0, l.size)
+	l.ListTraverse(func(data T) {
+		out = append(out, data)
+	})
+	return out
+}
+
+// Iterator returns an iterator over the node data from head to tail.
+func (l *LinkedList[T]) Iterator() Iterator[T] {
+	current := l.head
+	return NewIterator(func() (T, bool) {
+		if current == nil {
+			var zero T
+			return zero, false
+		}
+		data := current.Data
+		current = current.next
+		return data, true
+	})
+}
+
 // ListTraverse traverses the linked list and applies the given function to each node's data.
-func (l *LinkedList) ListTraverse(fn func(data NodeData)) {
-	current := l.Head
+func (l *LinkedList[T]) ListTraverse(fn func(data T)) {
+	current := l.head
 	for current != nil {
 		fn(current.Data)
-		current = current.Next
+		current = current.next
 	}
 }
 
-// ListFindInsertionPosition finds the position to insert a node with the given data
-// while keeping the list sorted.
-func (l *LinkedList) ListFindInsertionPosition(data NodeData) *Node {
-	current := l.Head
-	var prev *Node
+// ListFindInsertionPosition finds the node after which data should be inserted
+// to keep the list sorted. It returns nil when data belongs at the head.
+func (l *LinkedList[T]) ListFindInsertionPosition(data T, compare func(a, b T) int) *Node[T] {
+	current := l.head
+	var prev *Node[T]
 
-	for current != nil && data.(int) > current.Data.(int) {
+	for current != nil && compare(data, current.Data) > 0 {
 		prev = current
-		current = current.Next
+		current = current.next
 	}
 
 	return prev
 }
 
 // ListInsertionSortSinglyLinked sorts the linked list using the insertion sort algorithm.
-func (l *LinkedList) ListInsertionSortSinglyLinked() {
-	beforeCurrent := l.Head
-	curNode := l.Head.Next
+func (l *LinkedList[T]) ListInsertionSortSinglyLinked(compare func(a, b T) int) {
+	if l.head == nil {
+		return
+	}
+	beforeCurrent := l.head
+	curNode := l.head.next
 
 	for curNode != nil {
-		next := curNode.Next
-		position := l.ListFindInsertionPosition(curNode.Data)
+		next := curNode.next
+		position := l.ListFindInsertionPosition(curNode.Data, compare)
 
 		if position == beforeCurrent {
 			beforeCurrent = curNode
 		} else {
 			l.RemoveAfter(beforeCurrent)
-			if position == nil {
-				l.Prepend(curNode.Data)
-			} else {
-				l.InsertAfter(position, curNode.Data)
-			}
+			l.InsertAfter(position, curNode.Data)
 		}
 
 		curNode = next
 	}
 }
 
+// nodeAt returns the node at index, or nil if index is out of range.
+func (l *LinkedList[T]) nodeAt(index int) *Node[T] {
+	if index < 0 || index >= l.size {
+		return nil
+	}
+	current := l.head
+	for i := 0; i < index; i++ {
+		current = current.next
+	}
+	return current
+}
+
 // Display prints the elements of the linked list
-func (l *LinkedList) Display() {
-	if l.Head == nil {
+func (l *LinkedList[T]) Display() {
+	if l.head == nil {
 		fmt.Println(" [head] -> nil")
 		// Empty list, nothing to remove
 		return
 	}
-	current := l.Head
-	switch tail := l.Tail.Data.(type) {
-	case int:
-		fmt.Printf(" [tail]-> (%d)\n", tail)
+	current := l.head
+	switch tail := any(l.tail.Data).(type) {
 	case rune:
 		fmt.Printf(" [tail]-> (%c)\n", tail)
+	default:
+		fmt.Printf(" [tail]-> (%v)\n", tail)
 	}
 	fmt.Printf(" [head]-> ")
 	for current != nil {
-		switch data := current.Data.(type) {
+		switch data := any(current.Data).(type) {
 		case int:
 			fmt.Printf("(%d) -> ", data)
 		case rune:
@@ -260,40 +360,43 @@ func (l *LinkedList) Display() {
 		default:
 			fmt.Printf("Unknown type: %v -> ", reflect.TypeOf(current.Data))
 		}
-		current = current.Next
+		current = current.next
 	}
 	fmt.Println("nil")
 }
 
-func TestLinkedList(startList []NodeData) {
-	list := NewLinkedList()
+func TestLinkedList(startList []int) {
+	compare := cmp.Compare[int]
+	list := NewLinkedList[int]()
 	if len(startList) > 0 {
 		fmt.Println("initial list:")
-		for _, data := range startList {
-			list.Append(data)
-		}
+		list.Append(startList...)
 	}
 	list.Display()
 
-	//removeAfterTest := []NodeData{49, 0}
+	//removeAfterTest := []*Node[int]{list.Head(), nil}
 	//for _, remove := range removeAfterTest {
 	//	fmt.Printf("\nPost-RemoveAfter: %v\n", remove)
 	//	list.RemoveAfter(remove)
 	//	list.Display()
 	//}
-	searchTest := []NodeData{3, 6, 9}
+	searchTest := []int{3, 6, 9}
 	for _, search := range searchTest {
 		fmt.Printf("\nListSearch(%v): ", search)
-		found := list.ListSearch(search)
+		found := list.Find(search, compare)
 		if found != nil {
-			if found.Next == nil {
+			if found.Next() == nil {
 				fmt.Printf("(%v) -> <nil>\n", found.Data)
 			} else {
-				fmt.Printf("(%v) -> (%v)\n", found.Data, found.Next.Data)
+				fmt.Printf("(%v) -> (%v)\n", found.Data, found.Next().Data)
 			}
 
 		} else {
 			fmt.Printf(" <nil> \n")
 		}
 	}
+
+	list.ListInsertionSortSinglyLinked(compare)
+	fmt.Println("\nListInsertionSortSinglyLinked:")
+	list.Display()
 }
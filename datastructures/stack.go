@@ -1,65 +1,164 @@
 package datastructures
 
-import "fmt"
-
-// Stack represents a basic stack data structure.
-type Stack struct {
-	items []StackItem
+import (
+	"cmp"
+	"fmt"
+)
+
+// Stack represents a basic LIFO stack. Items are kept in a slice with the top of
+// the stack at the end, so Push and Pop are O(1) (amortized for Push).
+type Stack[T any] struct {
+	items []T
 }
 
-// StackItem represents a basic stack data item
-type StackItem interface{}
-
-func NewStack() *Stack {
-	return &Stack{}
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{}
 }
 
 // Push adds an item to the top of the stack.
-func (s *Stack) Push(item StackItem) {
-	s.items = append([]StackItem{item}, s.items...)
+func (s *Stack[T]) Push(item T) {
+	s.items = append(s.items, item)
 }
 
-// Pop removes and returns the top item from the stack. It returns nil if the stack is empty.
-func (s *Stack) Pop() StackItem {
+// Pop removes and returns the top item from the stack. It returns false if the stack is empty.
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
 	if len(s.items) == 0 {
-		return nil
+		return zero, false
 	}
 
-	topIndex := 0
+	topIndex := len(s.items) - 1
 	item := s.items[topIndex]
-	s.items = s.items[1:]
-	return item
+	s.items[topIndex] = zero // Release the reference so it can be collected
+	s.items = s.items[:topIndex]
+	return item, true
 }
 
-// Peek returns the top item from the stack without removing it. It returns nil if the stack is empty.
-func (s *Stack) Peek() StackItem {
+// Peek returns the top item from the stack without removing it. It returns false if the stack is empty.
+func (s *Stack[T]) Peek() (T, bool) {
 	if len(s.items) == 0 {
-		return nil
+		var zero T
+		return zero, false
 	}
-	return s.items[0]
+	return s.items[len(s.items)-1], true
 }
 
 // IsEmpty checks if the stack is empty.
-func (s *Stack) IsEmpty() bool {
+func (s *Stack[T]) IsEmpty() bool {
 	return len(s.items) == 0
 }
 
 // Size returns the number of items in the stack.
-func (s *Stack) Size() int {
+func (s *Stack[T]) Size() int {
 	return len(s.items)
 }
 
-// Print prints the current stack contents.
-func (s *Stack) Print() {
-	fmt.Printf("%v\n", s.items)
+// Clear removes every item from the stack.
+func (s *Stack[T]) Clear() {
+	s.items = nil
+}
+
+// Iterator returns an iterator over the items from the top of the stack to the bottom,
+// the order in which Pop would return them.
+func (s *Stack[T]) Iterator() Iterator[T] {
+	return indexIterator(len(s.items), true, func(i int) T { return s.items[i] })
+}
+
+// ReverseIterator returns an iterator over the items from the bottom of the stack to the top.
+func (s *Stack[T]) ReverseIterator() Iterator[T] {
+	return indexIterator(len(s.items), false, func(i int) T { return s.items[i] })
+}
+
+// Print prints the current stack contents, top first.
+func (s *Stack[T]) Print() {
+	fmt.Printf("%v\n", Collect(s.Iterator()))
+}
+
+// extremumStack is a stack that also tracks the "best" item it holds, where
+// compare(a, b) < 0 means a is better than b. A second stack records each new
+// best (ties included) as it is pushed, so the current best is always on top of
+// it and both Push and Pop stay O(1).
+type extremumStack[T any] struct {
+	Stack[T]
+	best    Stack[T]
+	compare func(a, b T) int
+}
+
+// Push adds an item to the top of the stack.
+func (s *extremumStack[T]) Push(item T) {
+	s.Stack.Push(item)
+	if top, ok := s.best.Peek(); !ok || s.compare(item, top) <= 0 {
+		s.best.Push(item)
+	}
+}
+
+// Pop removes and returns the top item from the stack. It returns false if the stack is empty.
+func (s *extremumStack[T]) Pop() (T, bool) {
+	item, ok := s.Stack.Pop()
+	if ok {
+		if top, _ := s.best.Peek(); s.compare(item, top) == 0 {
+			s.best.Pop()
+		}
+	}
+	return item, ok
+}
+
+// Clear removes every item from the stack.
+func (s *extremumStack[T]) Clear() {
+	s.Stack.Clear()
+	s.best.Clear()
 }
 
-func TestStack(initStack []StackItem) {
-	stack := &Stack{items: initStack}
+// MinStack is a Stack that reports its smallest item in O(1).
+type MinStack[T any] struct {
+	extremumStack[T]
+}
+
+// NewMinStack creates a new empty MinStack for an ordered type.
+func NewMinStack[T cmp.Ordered]() *MinStack[T] {
+	return NewMinStackFunc(cmp.Compare[T])
+}
+
+// NewMinStackFunc creates a new empty MinStack ordered by compare.
+func NewMinStackFunc[T any](compare func(a, b T) int) *MinStack[T] {
+	return &MinStack[T]{extremumStack[T]{compare: compare}}
+}
+
+// Min returns the smallest item on the stack. It returns false if the stack is empty.
+func (s *MinStack[T]) Min() (T, bool) {
+	return s.best.Peek()
+}
+
+// MaxStack is a Stack that reports its largest item in O(1).
+type MaxStack[T any] struct {
+	extremumStack[T]
+}
+
+// NewMaxStack creates a new empty MaxStack for an ordered type.
+func NewMaxStack[T cmp.Ordered]() *MaxStack[T] {
+	return NewMaxStackFunc(cmp.Compare[T])
+}
+
+// NewMaxStackFunc creates a new empty MaxStack ordered by compare.
+func NewMaxStackFunc[T any](compare func(a, b T) int) *MaxStack[T] {
+	reversed := func(a, b T) int { return compare(b, a) }
+	return &MaxStack[T]{extremumStack[T]{compare: reversed}}
+}
+
+// Max returns the largest item on the stack. It returns false if the stack is empty.
+func (s *MaxStack[T]) Max() (T, bool) {
+	return s.best.Peek()
+}
+
+func TestStack(initStack []int) {
+	stack := NewStack[int]()
+	for _, item := range initStack {
+		stack.Push(item)
+	}
 	fmt.Printf("initial stack: ")
 	stack.Print()
 
-	//peaker := stack.Peek()
+	//peaker, _ := stack.Peek()
 	//fmt.Printf("peek test -> peaker %v -> stack: ", peaker)
 	//stack.Print()
 	//
@@ -67,11 +166,11 @@ func TestStack(initStack []StackItem) {
 	//fmt.Printf("push test -> stack: ")
 	//stack.Print()
 
-	popper := stack.Pop()
+	popper, _ := stack.Pop()
 	fmt.Printf("pop test -> popped %v -> stack: ", popper)
 	stack.Print()
 
-	peaker := stack.Peek()
+	peaker, _ := stack.Peek()
 	fmt.Printf("peek test -> peaker %v -> stack: ", peaker)
 	stack.Print()
 
@@ -79,15 +178,30 @@ func TestStack(initStack []StackItem) {
 	//fmt.Printf("push test -> stack: ")
 	//stack.Print()
 	//
-	//popper = stack.Pop()
+	//popper, _ = stack.Pop()
 	//fmt.Printf("pop test -> popped %v -> stack: ", popper)
 	//stack.Print()
 	//
-	//peaker = stack.Peek()
+	//peaker, _ = stack.Peek()
 	//fmt.Printf("peek test -> peaker %v -> stack: ", peaker)
 	//stack.Print()
 
 	fmt.Println("final stack length:", stack.Size())
 	fmt.Println("empty:", stack.IsEmpty())
 
+	minStack, maxStack := NewMinStack[int](), NewMaxStack[int]()
+	for _, item := range initStack {
+		minStack.Push(item)
+		maxStack.Push(item)
+		lo, _ := minStack.Min()
+		hi, _ := maxStack.Max()
+		fmt.Printf("push %v -> min %v | max %v\n", item, lo, hi)
+	}
+	for !minStack.IsEmpty() {
+		item, _ := minStack.Pop()
+		maxStack.Pop()
+		lo, _ := minStack.Min()
+		hi, _ := maxStack.Max()
+		fmt.Printf("pop %v -> min %v | max %v\n", item, lo, hi)
+	}
 }
@@ -1,6 +1,7 @@
 package binary
 
 import (
+	"dsa/datastructures"
 	"fmt"
 	"strings"
 )
@@ -292,6 +293,26 @@ func pow(base, exp int) int {
 	return result
 }
 
+// Iterator returns an iterator over the tree's values in ascending (in-order) order.
+func (tree *Tree) Iterator() datastructures.Iterator[int] {
+	return inorderIterator(tree.Root, false)
+}
+
+// ReverseIterator returns an iterator over the tree's values in descending order.
+func (tree *Tree) ReverseIterator() datastructures.Iterator[int] {
+	return inorderIterator(tree.Root, true)
+}
+
+// inorderIterator walks the subtree rooted at root in-order.
+func inorderIterator(root *Node, reverse bool) datastructures.Iterator[int] {
+	return datastructures.InorderIterator(root,
+		func(n *Node) *Node { return n.Left },
+		func(n *Node) *Node { return n.Right },
+		func(n *Node) bool { return n == nil },
+		func(n *Node) int { return n.Value },
+		reverse)
+}
+
 // Print prints the binary tree in a visually appealing way
 func (tree *Tree) Print() {
 	lines, _, _ := tree.Root.visualize()
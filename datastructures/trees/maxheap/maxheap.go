@@ -1,6 +1,7 @@
 package maxheap
 
 import (
+	"dsa/datastructures"
 	"fmt"
 	"math"
 	"strconv"
@@ -73,6 +74,20 @@ func (h *MaxHeap) PercolateDown(index int) {
 	}
 }
 
+// Iterator returns an iterator over the heap's values in array (level) order:
+// the root first, then each level from left to right. Only the first value is
+// guaranteed to be the maximum; use Sort for descending order.
+func (h *MaxHeap) Iterator() datastructures.Iterator[int] {
+	i := -1
+	return datastructures.NewIterator(func() (int, bool) {
+		i++
+		if i >= len(h.heap) {
+			return 0, false
+		}
+		return h.heap[i], true
+	})
+}
+
 func (h *MaxHeap) Sort() *MaxHeap {
 	sorted := NewMaxHeap()
 	for len(h.heap) > 0 {
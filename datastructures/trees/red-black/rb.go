@@ -1,6 +1,7 @@
 package red_black
 
 import (
+	"dsa/datastructures"
 	"fmt"
 	"strings"
 )
@@ -109,19 +110,27 @@ func flipColors(node *Node) {
 
 // InorderTraversal returns the values of the red-black tree in sorted order.
 func (t *RedBlackTree) InorderTraversal() []int {
-	var result []int
-	inorder(t.Root, &result)
-	return result
+	return datastructures.Collect(t.Iterator())
 }
 
-// inorder recursively traverses the red-black tree in-order.
-func inorder(node *Node, result *[]int) {
-	if node == nil {
-		return
-	}
-	inorder(node.Left, result)
-	*result = append(*result, node.Value)
-	inorder(node.Right, result)
+// Iterator returns an iterator over the tree's values in ascending (in-order) order.
+func (t *RedBlackTree) Iterator() datastructures.Iterator[int] {
+	return inorderIterator(t.Root, false)
+}
+
+// ReverseIterator returns an iterator over the tree's values in descending order.
+func (t *RedBlackTree) ReverseIterator() datastructures.Iterator[int] {
+	return inorderIterator(t.Root, true)
+}
+
+// inorderIterator walks the subtree rooted at root in-order.
+func inorderIterator(root *Node, reverse bool) datastructures.Iterator[int] {
+	return datastructures.InorderIterator(root,
+		func(n *Node) *Node { return n.Left },
+		func(n *Node) *Node { return n.Right },
+		func(n *Node) bool { return n == nil },
+		func(n *Node) int { return n.Value },
+		reverse)
 }
 
 // Print prints the red-black tree in a visually appealing way with colors.
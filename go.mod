@@ -1,5 +1,5 @@
 module dsa
 
-go 1.21
+go 1.23
 
 require github.com/ryanuber/columnize v2.1.2+incompatible
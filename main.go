@@ -1,15 +1,59 @@
 package main
 
-import algo "dsa/algorithms"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	algo "dsa/algorithms"
+)
 
 func main() {
+	saveBaseline := flag.String("save-baseline", "", "run the default sorting benchmark and save it as a baseline to `file`")
+	baseline := flag.String("baseline", "", "rerun the sorting benchmark saved in `file` and exit 1 if an algorithm regressed")
+	flag.Parse()
+	if *saveBaseline != "" {
+		results, err := algo.RunBenchmark(algo.DefaultBenchmarkConfig())
+		if err == nil {
+			err = results.SaveBaseline(*saveBaseline)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+	if *baseline != "" {
+		err := algo.CheckBenchmarkBaseline(*baseline)
+		switch {
+		case errors.Is(err, algo.ErrBenchmarkRegression), errors.Is(err, algo.ErrTooFewTrials):
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		case err != nil:
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
 	//algo.BenchmarkSortAlgorithms()
-	// listData := []ds.NodeData{96, 12, 59}
+	// algo.TestBenchmarkRunner()
+	// algo.TestBenchmarkBudget()
+	// algo.TestBenchmarkMemory()
+	// algo.TestGenericSort()
+	// algo.TestSortingAlgorithms()
+	// algo.TestExternalSort()
+	// algo.TestBenchmarkExport()
+	// algo.TestBenchmarkBaseline()
+	// listData := []int{96, 12, 59}
 	// ds.TestDoublyLinkedList(listData)
-	//testItems := []datastructures.DequeValue{46, 74}
+	// ds.TestList()
+	//testItems := []int{46, 74}
 	//datastructures.TestDeque(testItems)
-	// testData := []datastructures.ArrayListData{19, 26, 47}
+	// datastructures.TestArrayList([]int{19, 26, 47})
 	// datastructures.TestHashTable()
+	// _ = datastructures.TestConcurrentQueue(8, 8, 5000, 4)
 	// var search []int
 	// var insert []int
 	// remove := []int{20}